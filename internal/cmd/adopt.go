@@ -0,0 +1,675 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/kintone/kpdev/internal/config"
+	"github.com/kintone/kpdev/internal/generator"
+	"github.com/kintone/kpdev/internal/plugin"
+	"github.com/kintone/kpdev/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+// adoptReportFile は kpdev adopt の取り込み結果レポート
+const adoptReportFile = "adopt-report.md"
+
+var (
+	flagAdoptKey            string
+	flagAdoptOut            string
+	flagAdoptDomain         string
+	flagAdoptUsername       string
+	flagAdoptPassword       string
+	flagAdoptPackageManager string
+)
+
+var adoptCmd = &cobra.Command{
+	Use:   "adopt <dir|zip>",
+	Short: "既存のプラグインを kpdev プロジェクトに移行",
+	Long: `@kintone/create-plugin などで作成した既存プラグインのソースディレクトリ、
+またはプラグインZIPから kpdev プロジェクトを作成します。
+
+既存の秘密鍵を本番用の鍵として取り込むため、プラグインIDは変わりません。
+manifest.json が参照する js / css / html は src/ の構成に配置し、
+https の外部URLは .kpdev/manifest.json にそのまま残します。
+移行できなかった内容は adopt-report.md に記録します。`,
+	Example: `  kpdev adopt ./my-plugin --key ./my-plugin/private.ppk
+  kpdev adopt my-plugin.zip --key private.ppk -o my-plugin-kpdev`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAdopt,
+}
+
+func init() {
+	rootCmd.AddCommand(adoptCmd)
+
+	adoptCmd.Flags().StringVarP(&flagAdoptKey, "key", "k", "", "既存プラグインの秘密鍵（.ppk）。ディレクトリの場合は private.ppk を自動で探す")
+	adoptCmd.Flags().StringVarP(&flagAdoptOut, "out", "o", "", "作成するプロジェクトのディレクトリ（デフォルト: プラグインの英語名）")
+	adoptCmd.Flags().StringVarP(&flagAdoptDomain, "domain", "d", "", "kintone ドメイン")
+	adoptCmd.Flags().StringVarP(&flagAdoptUsername, "username", "u", "", "kintone ユーザー名")
+	adoptCmd.Flags().StringVarP(&flagAdoptPassword, "password", "p", "", "kintone パスワード")
+	adoptCmd.Flags().StringVarP(&flagAdoptPackageManager, "package-manager", "m", "npm", "パッケージマネージャー (npm|pnpm|yarn|bun)")
+}
+
+// adoptResult は kpdev adopt の移行結果
+type adoptResult struct {
+	copied    map[string]string // 配置先（プロジェクトからの相対パス）→ 取り込み元
+	main      []string          // src/main/main.js から import するパス（src/main からの相対）
+	config    []string          // src/config/main.js から import するパス（src/config からの相対）
+	externals []string
+	warnings  []string
+}
+
+// build ツールなど、kpdev では不要になる依存パッケージ
+var adoptDropDependencies = map[string]bool{
+	"@kintone/plugin-packer":                 true,
+	"@kintone/plugin-uploader":               true,
+	"@kintone/webpack-plugin-kintone-plugin": true,
+	"webpack":                                true,
+	"webpack-cli":                            true,
+}
+
+// relativeImportPattern は JS の相対 import / require を検出する
+var relativeImportPattern = regexp.MustCompile(`(?:\bfrom\s*|\bimport\s*\(?\s*|\brequire\s*\(\s*)['"](\.{1,2}/[^'"]+)['"]`)
+
+func runAdopt(cmd *cobra.Command, args []string) error {
+	green := color.New(color.FgGreen).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	src, err := plugin.OpenSource(args[0])
+	if err != nil {
+		return fmt.Errorf("既存プラグインの読み込みエラー: %w", err)
+	}
+
+	// 秘密鍵（プラグインIDを維持するため必須）
+	keyPath := flagAdoptKey
+	if keyPath == "" && !src.IsZip {
+		for _, dir := range []string{src.Dir, filepath.Dir(src.Dir)} {
+			if candidate := filepath.Join(dir, "private.ppk"); fileExists(candidate) {
+				keyPath = candidate
+				break
+			}
+		}
+	}
+	if keyPath == "" {
+		return fmt.Errorf("プラグインIDを維持するため、--key で既存プラグインの秘密鍵を指定してください")
+	}
+	privateKey, err := generator.LoadPrivateKey(keyPath)
+	if err != nil {
+		return fmt.Errorf("秘密鍵の読み込みエラー: %w", err)
+	}
+	pluginID, err := generator.GeneratePluginID(privateKey)
+	if err != nil {
+		return err
+	}
+	if src.PluginID != "" && src.PluginID != pluginID {
+		return fmt.Errorf("秘密鍵のプラグインID（%s）が ZIP のプラグインID（%s）と一致しません", pluginID, src.PluginID)
+	}
+
+	nameJa, nameEn := manifestLocalized(src.Manifest, "name")
+	descJa, descEn := manifestLocalized(src.Manifest, "description")
+
+	outDir := flagAdoptOut
+	if outDir == "" {
+		outDir = adoptProjectName(nameEn, args[0])
+	}
+	if !filepath.IsAbs(outDir) {
+		outDir = filepath.Join(cwd, outDir)
+	}
+	if fileExists(filepath.Join(outDir, "package.json")) || fileExists(filepath.Join(outDir, config.ConfigDir)) {
+		rel, _ := filepath.Rel(cwd, outDir)
+		return fmt.Errorf("%s には既にプロジェクトがあります。--out で別のディレクトリを指定してください", rel)
+	}
+
+	_, hasDesktop := src.Manifest["desktop"]
+	_, hasMobile := src.Manifest["mobile"]
+	if !hasDesktop && !hasMobile {
+		hasDesktop = true
+	}
+
+	answers := &prompt.InitAnswers{
+		ProjectName:    filepath.Base(outDir),
+		PluginNameJa:   nameJa,
+		PluginNameEn:   nameEn,
+		DescriptionJa:  descJa,
+		DescriptionEn:  descEn,
+		Domain:         flagAdoptDomain,
+		Framework:      prompt.FrameworkVanilla,
+		Language:       prompt.LanguageJavaScript,
+		Username:       flagAdoptUsername,
+		Password:       flagAdoptPassword,
+		PackageManager: prompt.PackageManager(flagAdoptPackageManager),
+		TargetDesktop:  hasDesktop,
+		TargetMobile:   hasMobile,
+	}
+
+	fmt.Printf("\n%s 既存プラグインを移行中...\n", cyan("→"))
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return err
+	}
+
+	fmt.Printf("  テンプレート...")
+	if err := generator.GenerateProject(outDir, answers); err != nil {
+		fmt.Println()
+		return fmt.Errorf("プロジェクト生成エラー: %w", err)
+	}
+	fmt.Printf(" %s\n", green("✓"))
+
+	fmt.Printf("  ソースファイル...")
+	result, err := adoptSources(src, outDir)
+	if err != nil {
+		fmt.Println()
+		return fmt.Errorf("ソースファイルの移行エラー: %w", err)
+	}
+	if err := adoptManifest(src, outDir, result); err != nil {
+		fmt.Println()
+		return fmt.Errorf("manifest の移行エラー: %w", err)
+	}
+	if err := adoptPackageJSON(src, outDir); err != nil {
+		fmt.Println()
+		return fmt.Errorf("package.json の更新エラー: %w", err)
+	}
+	fmt.Printf(" %s\n", green("✓"))
+
+	fmt.Printf("  Vite設定...")
+	if err := generator.GenerateViteConfig(outDir, answers.Framework, answers.Language); err != nil {
+		fmt.Println()
+		return fmt.Errorf("Vite設定生成エラー: %w", err)
+	}
+	fmt.Printf(" %s\n", green("✓"))
+
+	fmt.Printf("  証明書...")
+	if err := generator.GenerateCerts(outDir); err != nil {
+		fmt.Println()
+		return fmt.Errorf("証明書生成エラー: %w", err)
+	}
+	fmt.Printf(" %s\n", green("✓"))
+
+	fmt.Printf("  秘密鍵...")
+	if _, err := generator.ImportProdKey(outDir, keyPath); err != nil {
+		fmt.Println()
+		return fmt.Errorf("秘密鍵の取り込みエラー: %w", err)
+	}
+	if err := generator.GenerateKeys(outDir); err != nil {
+		fmt.Println()
+		return fmt.Errorf("秘密鍵生成エラー: %w", err)
+	}
+	fmt.Printf(" %s\n", green("✓"))
+
+	fmt.Printf("  ローダー...")
+	if err := generator.GenerateLoader(outDir, answers, version); err != nil {
+		fmt.Println()
+		return fmt.Errorf("ローダー生成エラー: %w", err)
+	}
+	fmt.Printf(" %s\n", green("✓"))
+
+	fmt.Printf("  ESLint設定...")
+	if err := generator.GenerateESLintConfig(outDir, answers.Framework, answers.Language); err != nil {
+		fmt.Println()
+		return fmt.Errorf("ESLint設定生成エラー: %w", err)
+	}
+	fmt.Printf(" %s\n", green("✓"))
+
+	cfg := &config.Config{
+		SchemaVersion: config.CurrentSchemaVersion,
+		Kintone: config.KintoneConfig{
+			Dev: config.DevEnvConfig{
+				Domain: answers.Domain,
+				Auth: config.AuthConfig{
+					Username: answers.Username,
+					Password: answers.Password,
+				},
+			},
+		},
+		Dev: config.DevConfig{
+			Origin: generator.DevOrigin,
+			Entry: config.EntryConfig{
+				Main:   generator.GetEntryPath(answers.Framework, answers.Language, "main"),
+				Config: generator.GetEntryPath(answers.Framework, answers.Language, "config"),
+			},
+		},
+		Targets: config.TargetsConfig{
+			Desktop: answers.TargetDesktop,
+			Mobile:  answers.TargetMobile,
+		},
+		PackageManager: string(answers.PackageManager),
+	}
+	if err := cfg.Save(outDir); err != nil {
+		return fmt.Errorf("設定保存エラー: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(outDir, adoptReportFile), []byte(adoptReport(args[0], pluginID, result)), 0644); err != nil {
+		return fmt.Errorf("レポートの書き込みエラー: %w", err)
+	}
+
+	printAdoptSuccess(cwd, outDir, answers, pluginID, result)
+	return nil
+}
+
+// manifestLocalized は name / description の日本語・英語を返す（片方しかなければもう片方に揃える）
+func manifestLocalized(manifest map[string]interface{}, key string) (string, string) {
+	m, _ := manifest[key].(map[string]interface{})
+	ja, _ := m["ja"].(string)
+	en, _ := m["en"].(string)
+	if ja == "" {
+		ja = en
+	}
+	if en == "" {
+		en = ja
+	}
+	return ja, en
+}
+
+// adoptProjectName はプラグインの英語名からディレクトリ名を作る
+func adoptProjectName(nameEn, source string) string {
+	name := strings.ToLower(strings.TrimSpace(nameEn))
+	name = regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(name, "-")
+	name = strings.Trim(name, "-")
+	if name == "" {
+		base := filepath.Base(strings.TrimSuffix(source, filepath.Ext(source)))
+		name = base + "-kpdev"
+	}
+	return name
+}
+
+// adoptSources は manifest.json が参照する js / css / html を src/ に配置し、エントリーを生成する
+func adoptSources(src *plugin.Source, outDir string) (*adoptResult, error) {
+	result := &adoptResult{copied: map[string]string{}}
+
+	// 雛形のサンプルを取り除く（設定画面がない場合は雛形の設定画面を残す）
+	_, hasConfig := src.Manifest["config"].(map[string]interface{})
+	if err := os.RemoveAll(filepath.Join(outDir, "src", "main")); err != nil {
+		return nil, err
+	}
+	if hasConfig {
+		if err := os.RemoveAll(filepath.Join(outDir, "src", "config")); err != nil {
+			return nil, err
+		}
+	} else {
+		result.warnings = append(result.warnings, "manifest.json に設定画面（config）がないため、雛形の設定画面を配置しました")
+	}
+	// エントリーとして生成するため、取り込むファイルに使わせない
+	result.copied["src/main/main.js"] = ""
+	result.copied["src/config/main.js"] = ""
+	result.copied["src/config/index.html"] = ""
+
+	for _, target := range []string{"desktop", "mobile"} {
+		for _, kind := range []string{"js", "css"} {
+			for _, entry := range manifestResourceList(src.Manifest, target, kind) {
+				rel, err := adoptResource(src, outDir, "src/main", target, kind, entry, result)
+				if err != nil {
+					return nil, err
+				}
+				if rel != "" && !slices.Contains(result.main, rel) {
+					result.main = append(result.main, rel)
+				}
+			}
+		}
+	}
+
+	if hasConfig {
+		for _, kind := range []string{"js", "css"} {
+			for _, entry := range manifestResourceList(src.Manifest, "config", kind) {
+				rel, err := adoptResource(src, outDir, "src/config", "config", kind, entry, result)
+				if err != nil {
+					return nil, err
+				}
+				if rel != "" && !slices.Contains(result.config, rel) {
+					result.config = append(result.config, rel)
+				}
+			}
+		}
+
+		html := "<div id=\"config-root\"></div>\n"
+		if ref, _ := src.Manifest["config"].(map[string]interface{})["html"].(string); ref != "" {
+			data, err := src.Read(ref)
+			if err != nil {
+				result.warnings = append(result.warnings, fmt.Sprintf("設定画面の HTML %s が見つかりません", ref))
+			} else {
+				html = string(data)
+			}
+		}
+		if err := writeAdoptedFile(outDir, "src/config/index.html", []byte(html)); err != nil {
+			return nil, err
+		}
+		if err := writeAdoptedFile(outDir, "src/config/main.js", []byte(adoptEntry("設定画面", result.config))); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writeAdoptedFile(outDir, "src/main/main.js", []byte(adoptEntry("PC・モバイル", result.main))); err != nil {
+		return nil, err
+	}
+
+	// アイコン（kpdev はプロジェクト直下の icon.png を使う）
+	if ref, _ := src.Manifest["icon"].(string); ref != "" {
+		data, err := src.Read(ref)
+		if err != nil {
+			result.warnings = append(result.warnings, fmt.Sprintf("アイコン %s が見つからないため、仮のアイコンを配置しました", ref))
+		} else {
+			if !strings.EqualFold(path.Ext(ref), ".png") {
+				result.warnings = append(result.warnings, fmt.Sprintf("アイコン %s は PNG ではありません。icon.png として配置したので、PNG に変換してください", ref))
+			}
+			if err := os.WriteFile(filepath.Join(outDir, "icon.png"), data, 0644); err != nil {
+				return nil, err
+			}
+			result.copied["icon.png"] = ref
+		}
+	}
+
+	// 取り込まなかったファイル
+	used := map[string]bool{"manifest.json": true}
+	for _, from := range result.copied {
+		used[from] = true
+	}
+	if ref, ok := src.Manifest["config"].(map[string]interface{}); ok {
+		if html, _ := ref["html"].(string); html != "" {
+			used[path.Clean(html)] = true
+		}
+	}
+	for _, f := range src.Files() {
+		if !used[f] && !strings.HasSuffix(f, ".ppk") {
+			result.warnings = append(result.warnings, fmt.Sprintf("%s は manifest.json から参照されていないため取り込んでいません", f))
+		}
+	}
+
+	return result, nil
+}
+
+// manifestResourceList は manifest.json の desktop / mobile / config の js・css を返す
+func manifestResourceList(manifest map[string]interface{}, target, kind string) []string {
+	t, _ := manifest[target].(map[string]interface{})
+	entries, _ := t[kind].([]interface{})
+	var list []string
+	for _, e := range entries {
+		if s, ok := e.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// adoptResource は manifest.json の1エントリーを取り込み、エントリーから import するパスを返す
+// 外部URLや取り込めないファイルの場合は空文字を返す
+func adoptResource(src *plugin.Source, outDir, destDir, target, kind, entry string, result *adoptResult) (string, error) {
+	if strings.HasPrefix(entry, "https://") {
+		result.externals = append(result.externals, target+"."+kind+": "+entry)
+		return "", nil
+	}
+	if strings.HasPrefix(entry, "http://") {
+		result.warnings = append(result.warnings, fmt.Sprintf("%s の %s %s は https ではないため削除しました", target, kind, entry))
+		return "", nil
+	}
+	if !src.Exists(entry) {
+		result.warnings = append(result.warnings, fmt.Sprintf("%s の %s %s が見つかりません", target, kind, entry))
+		return "", nil
+	}
+
+	// js/desktop.js → src/main/desktop.js のように、先頭のディレクトリ（js/・css/）を取り除いて配置する
+	rel := path.Clean(entry)
+	if i := strings.Index(rel, "/"); i >= 0 {
+		rel = rel[i+1:]
+	}
+	dest := path.Join(destDir, rel)
+	if from, ok := result.copied[dest]; ok && from != path.Clean(entry) {
+		dest = uniqueAdoptPath(result, dest)
+	}
+
+	if err := adoptFile(src, outDir, path.Clean(entry), dest, result); err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(dest, destDir+"/"), nil
+}
+
+// adoptFile はファイルを配置し、JS の場合は相対 import しているファイルも同じ相対位置に配置する
+func adoptFile(src *plugin.Source, outDir, from, dest string, result *adoptResult) error {
+	if existing, ok := result.copied[dest]; ok {
+		if existing != from {
+			result.warnings = append(result.warnings, fmt.Sprintf("%s と %s の配置先（%s）が重複するため、%s を取り込んでいません", existing, from, dest, from))
+		}
+		return nil
+	}
+
+	data, err := src.Read(from)
+	if err != nil {
+		return err
+	}
+	if err := writeAdoptedFile(outDir, dest, data); err != nil {
+		return err
+	}
+	result.copied[dest] = from
+
+	switch path.Ext(from) {
+	case ".js", ".mjs", ".cjs", ".jsx", ".ts", ".tsx":
+	default:
+		return nil
+	}
+
+	for _, m := range relativeImportPattern.FindAllStringSubmatch(string(data), -1) {
+		spec := m[1]
+		resolved := resolveAdoptImport(src, path.Join(path.Dir(from), spec))
+		if resolved == "" {
+			result.warnings = append(result.warnings, fmt.Sprintf("%s が import している %s が見つかりません", from, spec))
+			continue
+		}
+
+		// import 元との相対位置を保ったまま配置する
+		depDest := path.Join(path.Dir(dest), relativeSlashPath(path.Dir(from), resolved))
+		if strings.HasPrefix(depDest, "../") || !strings.HasPrefix(depDest, "src/") {
+			result.warnings = append(result.warnings, fmt.Sprintf("%s が import している %s は src/ の外に配置されるため取り込んでいません", from, spec))
+			continue
+		}
+		if err := adoptFile(src, outDir, resolved, depDest, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveAdoptImport は拡張子の省略・ディレクトリの index を解決する
+func resolveAdoptImport(src *plugin.Source, p string) string {
+	p = path.Clean(p)
+	candidates := []string{p}
+	for _, ext := range []string{".js", ".mjs", ".jsx", ".ts", ".tsx", ".json"} {
+		candidates = append(candidates, p+ext)
+	}
+	for _, ext := range []string{".js", ".ts"} {
+		candidates = append(candidates, p+"/index"+ext)
+	}
+	for _, c := range candidates {
+		if src.Exists(c) {
+			return c
+		}
+	}
+	return ""
+}
+
+// relativeSlashPath は / 区切りのパスで base から target への相対パスを返す
+func relativeSlashPath(base, target string) string {
+	rel, err := filepath.Rel(filepath.FromSlash(base), filepath.FromSlash(target))
+	if err != nil {
+		return target
+	}
+	return filepath.ToSlash(rel)
+}
+
+func uniqueAdoptPath(result *adoptResult, dest string) string {
+	ext := path.Ext(dest)
+	base := strings.TrimSuffix(dest, ext)
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d%s", base, i, ext)
+		if _, ok := result.copied[candidate]; !ok {
+			return candidate
+		}
+	}
+}
+
+func writeAdoptedFile(outDir, rel string, data []byte) error {
+	dst := filepath.Join(outDir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0644)
+}
+
+// adoptEntry は取り込んだファイルを manifest.json の読み込み順に import するエントリーを生成する
+func adoptEntry(label string, imports []string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "// kpdev adopt で取り込んだ%sのファイル（manifest.json の読み込み順）\n", label)
+	for _, rel := range imports {
+		fmt.Fprintf(&sb, "import './%s'\n", rel)
+	}
+	if len(imports) == 0 {
+		sb.WriteString("\nexport {}\n")
+	}
+	return sb.String()
+}
+
+// adoptManifest は既存の manifest.json を .kpdev/manifest.json に移行する
+// js / css / html / icon は kpdev のビルド成果物のパスに置き換え、外部URLはバンドルより前に残す
+func adoptManifest(src *plugin.Source, outDir string, result *adoptResult) error {
+	manifest := map[string]interface{}{}
+	for k, v := range src.Manifest {
+		manifest[k] = v
+	}
+	manifest["icon"] = "icon.png"
+
+	for _, target := range []string{"desktop", "mobile", "config"} {
+		if _, ok := manifest[target].(map[string]interface{}); !ok {
+			continue
+		}
+		t := map[string]interface{}{}
+		for k, v := range manifest[target].(map[string]interface{}) {
+			t[k] = v
+		}
+
+		for _, kind := range []string{"js", "css"} {
+			t[kind] = append(config.ManifestExternalURLs(manifest, target, kind), kind+"/"+target+"."+kind)
+		}
+		if target == "config" {
+			t["html"] = "html/config.html"
+		}
+		manifest[target] = t
+	}
+
+	data := config.MarshalManifestJSON(manifest)
+	return os.WriteFile(filepath.Join(config.GetConfigDir(outDir), "manifest.json"), []byte(data), 0644)
+}
+
+// adoptPackageJSON は既存プロジェクトの dependencies とバージョンを package.json に引き継ぐ
+func adoptPackageJSON(src *plugin.Source, outDir string) error {
+	deps := map[string]string{}
+	if !src.IsZip {
+		for _, dir := range []string{src.Dir, filepath.Dir(src.Dir)} {
+			data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+			if err != nil {
+				continue
+			}
+			var pkg struct {
+				Dependencies map[string]string `json:"dependencies"`
+			}
+			if err := json.Unmarshal(data, &pkg); err != nil {
+				continue
+			}
+			for name, v := range pkg.Dependencies {
+				if !adoptDropDependencies[name] {
+					deps[name] = v
+				}
+			}
+			break
+		}
+	}
+
+	version := ""
+	if v, ok := src.Manifest["version"]; ok {
+		version = fmt.Sprintf("%v", v)
+	}
+	return generator.MergePackageDependencies(outDir, deps, version)
+}
+
+// adoptReport は移行結果のレポート（Markdown）を生成する
+func adoptReport(source, pluginID string, result *adoptResult) string {
+	var sb strings.Builder
+	sb.WriteString("# kpdev adopt の移行結果\n\n")
+	fmt.Fprintf(&sb, "- 移行元: %s\n", source)
+	fmt.Fprintf(&sb, "- プラグインID: %s（本番用の鍵を引き継ぎ）\n", pluginID)
+	fmt.Fprintf(&sb, "- 移行日時: %s\n", time.Now().Format("2006-01-02 15:04:05"))
+
+	sb.WriteString("\n## 配置したファイル\n\n")
+	count := 0
+	for _, dest := range slices.Sorted(maps.Keys(result.copied)) {
+		if from := result.copied[dest]; from != "" {
+			fmt.Fprintf(&sb, "- `%s` → `%s`\n", from, dest)
+			count++
+		}
+	}
+	if count == 0 {
+		sb.WriteString("なし\n")
+	}
+
+	sb.WriteString("\n## 外部URL（.kpdev/manifest.json に保持）\n\n")
+	if len(result.externals) == 0 {
+		sb.WriteString("なし\n")
+	}
+	for _, e := range result.externals {
+		fmt.Fprintf(&sb, "- %s\n", e)
+	}
+
+	sb.WriteString("\n## 移行できなかった内容\n\n")
+	if len(result.warnings) == 0 {
+		sb.WriteString("なし\n")
+	}
+	for _, w := range result.warnings {
+		fmt.Fprintf(&sb, "- %s\n", w)
+	}
+
+	sb.WriteString("\n## 確認事項\n\n")
+	sb.WriteString("- 取り込んだファイルは ES モジュールとしてバンドルされます。ファイル間でグローバル変数・関数を共有している場合は `window` のプロパティとして参照してください\n")
+	sb.WriteString("- PC用とモバイル用のファイルは同じバンドルに含まれます\n")
+	sb.WriteString("- 移行元の秘密鍵は .kpdev/keys/private.prod.ppk に保存されています。移行元の鍵と同様に管理してください\n")
+	return sb.String()
+}
+
+func printAdoptSuccess(cwd, outDir string, answers *prompt.InitAnswers, pluginID string, result *adoptResult) {
+	green := color.New(color.FgGreen).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	fmt.Printf("\n%s 既存プラグインを移行しました!\n", green("✓"))
+
+	meta, _ := generator.LoadLoaderMeta(outDir)
+	fmt.Printf("\nPlugin ID:\n")
+	if meta != nil {
+		fmt.Printf("  Dev:  %s\n", cyan(meta.PluginIDs.Dev))
+	}
+	fmt.Printf("  Prod: %s（移行元と同じ）\n", cyan(pluginID))
+
+	if len(result.warnings) > 0 {
+		fmt.Printf("\n%s 移行できなかった内容が %d 件あります（%s）\n", yellow("⚠"), len(result.warnings), adoptReportFile)
+	}
+
+	rel, _ := filepath.Rel(cwd, outDir)
+	fmt.Printf("\n次のステップ:\n")
+	if rel != "." {
+		fmt.Printf("  %s %s\n", cyan("cd"), rel)
+	}
+	fmt.Printf("  %s\n", cyan(string(answers.PackageManager)+" install"))
+	if answers.Domain == "" {
+		fmt.Printf("  %s\n", cyan("kpdev config"))
+	}
+	fmt.Printf("  %s\n", cyan("kpdev dev"))
+	fmt.Println()
+}
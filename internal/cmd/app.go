@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/kintone/kpdev/internal/config"
+	"github.com/kintone/kpdev/internal/kintone"
+	"github.com/kintone/kpdev/internal/prompt"
+	"github.com/kintone/kpdev/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagAppFrom    string
+	flagAppRecords string
+	flagAppForce   bool
+)
+
+var appCmd = &cobra.Command{
+	Use:   "app",
+	Short: "開発環境のテストアプリを管理",
+}
+
+var appCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "スキーマからテストアプリを作成",
+	Long: `.kpdev/test-app.json の定義から開発環境にテストアプリを作成します。
+
+アプリの作成・フィールドの追加・運用環境への反映を行い、フィクスチャがあればレコードを登録します。
+作成したアプリIDは .kpdev/config.json に保存され、kpdev dev で開発用プラグインが追加されます。`,
+	Example: `  kpdev app create
+  kpdev app create --from .kpdev/test-app.json --records .kpdev/sandbox/records.json`,
+	RunE: runAppCreate,
+}
+
+var appDestroyCmd = &cobra.Command{
+	Use:   "destroy",
+	Short: "テストアプリを片付ける",
+	Long: `kpdev app create で作成したテストアプリのレコードを削除し、保存したアプリIDを消去します。
+
+kintone の REST API ではアプリ自体を削除できないため、アプリはアプリの設定画面から削除してください。`,
+	RunE: runAppDestroy,
+}
+
+func init() {
+	rootCmd.AddCommand(appCmd)
+	appCmd.AddCommand(appCreateCmd)
+	appCmd.AddCommand(appDestroyCmd)
+
+	appCreateCmd.Flags().StringVar(&flagAppFrom, "from", filepath.Join(config.ConfigDir, config.TestAppFile), "テストアプリのスキーマファイル")
+	appCreateCmd.Flags().StringVar(&flagAppRecords, "records", "", "登録するレコードのフィクスチャ（スキーマの records より優先）")
+	appCreateCmd.Flags().BoolVarP(&flagAppForce, "force", "f", false, "作成済みのテストアプリがあっても新しく作成する")
+	appDestroyCmd.Flags().BoolVarP(&flagAppForce, "force", "f", false, "確認ダイアログをスキップ（CI/CD向け）")
+}
+
+func runAppCreate(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		return fmt.Errorf("設定ファイルが見つかりません。先に kpdev init を実行してください: %w", err)
+	}
+
+	if existing := cfg.Kintone.Dev.TestApp; existing != nil && !flagAppForce {
+		return fmt.Errorf("テストアプリ（ID: %s）が作成済みです。kpdev app destroy で片付けるか、--force を指定してください", existing.ID)
+	}
+
+	schemaPath := flagAppFrom
+	if !filepath.IsAbs(schemaPath) {
+		schemaPath = filepath.Join(cwd, schemaPath)
+	}
+	schema, err := config.LoadTestAppSchema(schemaPath)
+	if err != nil {
+		return fmt.Errorf("スキーマの読み込みエラー: %w", err)
+	}
+
+	// フィクスチャを読み込み（API を呼ぶ前に形式と添付ファイルを確認する）
+	recordsPath := schema.Records
+	if flagAppRecords != "" {
+		recordsPath = flagAppRecords
+		if !filepath.IsAbs(recordsPath) {
+			recordsPath = filepath.Join(cwd, recordsPath)
+		}
+	}
+	var loaded []kintone.Record
+	var uploads []string
+	if recordsPath != "" {
+		loaded, err = kintone.LoadRecordsFile(recordsPath)
+		if err != nil {
+			return fmt.Errorf("フィクスチャの読み込みエラー: %w", err)
+		}
+		uploads, err = fixtureAttachments(recordsPath, loaded)
+		if err != nil {
+			return err
+		}
+	}
+
+	client, err := newDevClient(cwd, cfg)
+	if err != nil {
+		return err
+	}
+
+	// アプリを作成する前にアップロードを済ませる（失敗しても不要なアプリが残らないように）
+	if err := uploadFixtureAttachments(client, recordsPath, loaded, len(uploads)); err != nil {
+		return err
+	}
+	records := make([]kintone.Record, 0, len(loaded))
+	for _, r := range loaded {
+		records = append(records, kintone.WritableRecord(r))
+	}
+
+	var appID string
+	err = ui.SpinnerWithResult(fmt.Sprintf("アプリ「%s」を作成中...", schema.Name), func() error {
+		var createErr error
+		appID, createErr = client.CreatePreviewApp(schema.Name)
+		return createErr
+	})
+	if err != nil {
+		return fmt.Errorf("アプリ作成エラー: %w", err)
+	}
+
+	// 作成した時点で保存する（以降で失敗しても destroy で片付けられるように）
+	cfg.Kintone.Dev.TestApp = &config.TestAppConfig{
+		ID:        appID,
+		Name:      schema.Name,
+		CreatedAt: time.Now().Format(time.RFC3339),
+	}
+	if err := cfg.Save(cwd); err != nil {
+		return fmt.Errorf("設定の保存に失敗しました: %w", err)
+	}
+
+	properties := make(map[string]interface{}, len(schema.Fields))
+	for code, field := range schema.Fields {
+		properties[code] = field
+	}
+	err = ui.SpinnerWithResult(fmt.Sprintf("フィールドを追加中（%d件）...", len(properties)), func() error {
+		return client.AddFormFields(appID, properties)
+	})
+	if err != nil {
+		return fmt.Errorf("フィールド追加エラー: %w", err)
+	}
+
+	err = ui.SpinnerWithResult("アプリを運用環境に反映中...", func() error {
+		return client.DeployApp(appID)
+	})
+	if err != nil {
+		return fmt.Errorf("アプリ反映エラー: %w", err)
+	}
+
+	if len(records) > 0 {
+		err = ui.SpinnerWithResult(fmt.Sprintf("レコードを登録中（%d件）...", len(records)), func() error {
+			return client.AddRecords(appID, records)
+		})
+		if err != nil {
+			return fmt.Errorf("レコード登録エラー: %w", err)
+		}
+	}
+
+	fmt.Println()
+	ui.Success(fmt.Sprintf("テストアプリを作成しました（ID: %s）", appID))
+	fmt.Println()
+
+	fmt.Printf("アプリ:\n")
+	fmt.Printf("  %s\n", ui.InfoStyle.Render(devAppURL(cfg, appID)))
+	fmt.Println()
+
+	fmt.Printf("kpdev dev を実行すると、このアプリに開発用プラグインが追加されます。\n")
+	return nil
+}
+
+func runAppDestroy(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		return fmt.Errorf("設定ファイルが見つかりません。先に kpdev init を実行してください: %w", err)
+	}
+
+	testApp := cfg.Kintone.Dev.TestApp
+	if testApp == nil {
+		ui.Info("テストアプリは作成されていません")
+		return nil
+	}
+
+	if !flagAppForce {
+		confirm, err := prompt.AskConfirm(fmt.Sprintf("テストアプリ「%s」（ID: %s）のレコードをすべて削除しますか?", testApp.Name, testApp.ID), false)
+		if err != nil {
+			if errors.Is(err, huh.ErrUserAborted) {
+				return nil
+			}
+			return err
+		}
+		if !confirm {
+			fmt.Println("キャンセルしました")
+			return nil
+		}
+	}
+
+	client, err := newDevClient(cwd, cfg)
+	if err != nil {
+		return err
+	}
+
+	var deleted int
+	err = ui.SpinnerWithResult("レコードを削除中...", func() error {
+		var deleteErr error
+		deleted, deleteErr = client.DeleteAllRecords(testApp.ID)
+		return deleteErr
+	})
+	if err != nil {
+		return fmt.Errorf("レコード削除エラー: %w", err)
+	}
+
+	cfg.Kintone.Dev.TestApp = nil
+	if err := cfg.Save(cwd); err != nil {
+		return fmt.Errorf("設定の保存に失敗しました: %w", err)
+	}
+
+	fmt.Println()
+	ui.Success(fmt.Sprintf("テストアプリのレコードを削除しました（%d件）", deleted))
+	ui.Warn("アプリ自体は REST API で削除できないため、アプリの設定画面から削除してください:")
+	fmt.Printf("  %s\n", ui.InfoStyle.Render(devAppURL(cfg, testApp.ID)))
+	return nil
+}
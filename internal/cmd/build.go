@@ -9,19 +9,24 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/kintone/kpdev/internal/config"
-	"github.com/kintone/kpdev/internal/generator"
 	"github.com/kintone/kpdev/internal/plugin"
 	"github.com/kintone/kpdev/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 var (
-	flagBuildMode   string
-	flagSkipVersion bool
+	flagBuildMode        string
+	flagSkipVersion      bool
+	flagBuildExternals   []string
+	flagBuildVerbose     bool
+	flagBuildNoCache     bool
+	flagBuildVariant     string
+	flagBuildAllVariants bool
 )
 
 var buildCmd = &cobra.Command{
@@ -31,15 +36,32 @@ var buildCmd = &cobra.Command{
 
 モード:
   prod (デフォルト) - 本番用ビルド (minify + console削除)
-  pre              - プレビルド (minifyなし + console残す + 名前に[開発]付与)`,
+  pre              - プレビルド (minifyなし + console残す + 名前に[開発]付与)
+  その他           - .kpdev/config.json の build.profiles に定義したビルドプロファイル
+
+.kpdev/config.json の build.externals（または --externals）に指定したパッケージは
+バンドルせず、インストール済みのバージョンの js.cybozu.com の UMD 版を読み込みます。
+
+--variant / --all-variants では .kpdev/variants.json に定義したバリアント（ホワイトラベル版）ごとに
+名前・アイコン・署名鍵を変えた ZIP を生成します。`,
+	Example: `  kpdev build --mode prod
+  kpdev build --mode staging
+  kpdev build --mode prod --variant acme
+  kpdev build --mode prod --all-variants
+  kpdev build --mode prod --externals react,react-dom`,
 	RunE: runBuild,
 }
 
 func init() {
 	rootCmd.AddCommand(buildCmd)
 
-	buildCmd.Flags().StringVar(&flagBuildMode, "mode", "prod", "ビルドモード (prod|pre)")
+	buildCmd.Flags().StringVar(&flagBuildMode, "mode", "prod", "ビルドモード (prod|pre|build.profiles のプロファイル名)")
 	buildCmd.Flags().BoolVar(&flagSkipVersion, "skip-version", false, "バージョン確認をスキップ")
+	buildCmd.Flags().BoolVar(&flagBuildVerbose, "verbose", false, "vite build の出力を表示")
+	buildCmd.Flags().BoolVar(&flagBuildNoCache, "no-cache", false, "ビルドキャッシュを使わずにすべてのバンドルをビルド")
+	buildCmd.Flags().StringVar(&flagBuildVariant, "variant", "", "バリアント（.kpdev/variants.json）を指定してビルド")
+	buildCmd.Flags().BoolVar(&flagBuildAllVariants, "all-variants", false, "すべてのバリアントをビルド")
+	buildCmd.Flags().StringSliceVar(&flagBuildExternals, "externals", nil, "CDN から読み込むパッケージ（build.externals より優先、\"\" ですべてバンドル）")
 }
 
 func runBuild(cmd *cobra.Command, args []string) error {
@@ -48,11 +70,16 @@ func runBuild(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		return fmt.Errorf("設定ファイルが見つかりません。先に kpdev init を実行してください: %w", err)
+	}
+
 	// モードを決定
 	buildMode := flagBuildMode
 	if !cmd.Flags().Changed("mode") {
 		// --mode が指定されていない場合は対話で選択
-		selectedMode, err := askBuildMode()
+		selectedMode, err := askBuildMode(cfg)
 		if err != nil {
 			if errors.Is(err, huh.ErrUserAborted) {
 				return nil
@@ -60,19 +87,39 @@ func runBuild(cmd *cobra.Command, args []string) error {
 			return err
 		}
 		buildMode = selectedMode
-	} else {
-		// モードを検証
-		if buildMode != "prod" && buildMode != "pre" {
-			return fmt.Errorf("無効なモードです: %s (prod または pre を指定してください)", buildMode)
-		}
 	}
-
-	isPre := buildMode == "pre"
-
-	// メタデータを読み込み
-	meta, err := generator.LoadLoaderMeta(cwd)
+	profile, err := cfg.Profile(buildMode)
 	if err != nil {
-		return fmt.Errorf("loader.meta.json が見つかりません。先に kpdev init を実行してください: %w", err)
+		return err
+	}
+
+	// バリアントを決定（ビルド前に定義を確認する）
+	if flagBuildVariant != "" && flagBuildAllVariants {
+		return fmt.Errorf("--variant と --all-variants は同時に指定できません")
+	}
+	var (
+		variantsFile *config.Variants
+		variants     []*config.Variant
+	)
+	if flagBuildVariant != "" || flagBuildAllVariants {
+		variantsFile, err = config.LoadVariants(cwd)
+		if err != nil {
+			return fmt.Errorf("%s の読み込みに失敗しました: %w", config.VariantsFile, err)
+		}
+		names := []string{flagBuildVariant}
+		if flagBuildAllVariants {
+			names = variantsFile.Names()
+			if len(names) == 0 {
+				return fmt.Errorf(".kpdev/%s にバリアントが定義されていません", config.VariantsFile)
+			}
+		}
+		for _, name := range names {
+			variant, err := variantsFile.Get(name)
+			if err != nil {
+				return err
+			}
+			variants = append(variants, variant)
+		}
 	}
 
 	// マニフェストを読み込み、バージョン確認
@@ -109,49 +156,196 @@ func runBuild(cmd *cobra.Command, args []string) error {
 	}
 
 	// モード表示
-	if isPre {
-		ui.Info("プレビルドを開始... (minifyなし, console残す, 名前に[開発]付与)")
-	} else {
+	switch buildMode {
+	case "prod":
 		ui.Info("本番ビルドを開始...")
+	case "pre":
+		ui.Info("プレビルドを開始... (minifyなし, console残す, 名前に[開発]付与)")
+	default:
+		ui.Info(fmt.Sprintf("%s ビルドを開始... (%s)", buildMode, profile.Summary()))
 	}
 	fmt.Println()
 
-	opts := &plugin.BuildOptions{
-		Mode:          buildMode,
-		Minify:        !isPre,
-		RemoveConsole: !isPre,
+	// バリアントを指定しなければ通常のビルドを1回行う
+	targets := variants
+	if len(targets) == 0 {
+		targets = []*config.Variant{nil}
 	}
+	variantsChanged := false
+	for i, variant := range targets {
+		opts := &plugin.BuildOptions{
+			Profile:  profile,
+			NoCache:  flagBuildNoCache,
+			Variant:  variant,
+			KeepZips: i > 0,
+		}
+		if cmd.Flags().Changed("externals") {
+			opts.Externals = append([]string{}, flagBuildExternals...)
+		}
 
-	var zipPath string
-	err = ui.SpinnerWithResult("バンドル中...", func() error {
-		var buildErr error
-		zipPath, buildErr = plugin.Build(cwd, opts)
-		return buildErr
-	})
-	if err != nil {
-		fmt.Println()
-		return err
+		spinnerLabel := "バンドル中..."
+		if variant != nil {
+			spinnerLabel = fmt.Sprintf("%s をバンドル中...", variant.Name)
+		}
+
+		// --verbose では Vite の出力を流すため、スピナーを使わない
+		var result *plugin.BuildResult
+		if flagBuildVerbose {
+			opts.Log = os.Stdout
+			result, err = plugin.Build(cwd, opts)
+		} else {
+			err = ui.SpinnerWithResult(spinnerLabel, func() error {
+				var buildErr error
+				result, buildErr = plugin.Build(cwd, opts)
+				return buildErr
+			})
+		}
+		if err != nil {
+			fmt.Println()
+			if variant != nil {
+				return fmt.Errorf("バリアント %s: %w", variant.Name, err)
+			}
+			return err
+		}
+
+		printBuildResult(cfg, buildMode, profile, variant, result)
+
+		// バリアントのプラグインIDを記録（鍵が変わった場合は警告）
+		if variant != nil && variant.PluginID != result.PluginID {
+			if variant.PluginID != "" {
+				ui.Warn(fmt.Sprintf("%s のプラグインIDが変わりました: %s → %s（秘密鍵 %s を確認してください）", variant.Name, variant.PluginID, result.PluginID, variant.KeyPath(cwd)))
+				fmt.Println()
+			}
+			variant.PluginID = result.PluginID
+			variantsChanged = true
+		}
+	}
+
+	if variantsChanged {
+		if err := variantsFile.Save(cwd); err != nil {
+			return fmt.Errorf("%s の保存に失敗しました: %w", config.VariantsFile, err)
+		}
+	}
+
+	return nil
+}
+
+// printBuildResult はビルドの結果（プラグインID・出力ファイル・所要時間）を表示する
+func printBuildResult(cfg *config.Config, buildMode string, profile *config.ResolvedProfile, variant *config.Variant, result *plugin.BuildResult) {
+	label := ""
+	if variant != nil {
+		label = variant.Name + ": "
 	}
 
-	// 結果を表示
 	fmt.Println()
-	if isPre {
-		ui.Success("プレビルド完了!")
-	} else {
-		ui.Success("ビルド完了!")
+	switch buildMode {
+	case "prod":
+		ui.Success(label + "ビルド完了!")
+	case "pre":
+		ui.Success(label + "プレビルド完了!")
+	default:
+		ui.Success(fmt.Sprintf("%s%s ビルド完了!", label, buildMode))
 	}
 
 	fmt.Printf("\nPlugin ID:\n")
-	if isPre {
-		fmt.Printf("  %s\n", ui.InfoStyle.Render(meta.PluginIDs.Dev))
+	fmt.Printf("  %s\n", ui.InfoStyle.Render(result.PluginID))
+
+	fmt.Printf("\n出力ファイル:\n")
+	fmt.Printf("  %s\n\n", ui.InfoStyle.Render(result.ZipPath))
+
+	if len(result.Assets) > 0 {
+		printAssets(result, cfg.AssetsBaseURL())
+	}
+
+	if len(result.Sizes) > 0 {
+		printOutputSizes(result)
+	}
+
+	if profile.Sourcemap == "hidden" {
+		fmt.Printf("ソースマップ（ZIPには含まれません）:\n")
+		fmt.Printf("  %s\n\n", ui.InfoStyle.Render(filepath.Join(filepath.Dir(result.ZipPath), "sourcemaps")))
+	}
+
+	if !ui.Quiet {
+		printBuildTimings(result)
+	}
+}
+
+// printAssets は dist/assets/ にコピーしたアセットの一覧を、配置先の URL とともに表示する
+func printAssets(result *plugin.BuildResult, baseURL string) {
+	var total int64
+	width := 0
+	for _, asset := range result.Assets {
+		total += asset.Size
+		width = max(width, lipgloss.Width(asset.Path))
+	}
+	outDir := plugin.AssetsOutDir(filepath.Dir(result.ZipPath))
+	fmt.Printf("アセット: %d 件（%s）\n", len(result.Assets), ui.InfoStyle.Render(config.FormatSize(total)))
+	fmt.Printf("  %s\n", ui.MutedStyle.Render(fmt.Sprintf("%s を %s に配置してください（ZIPには含まれません）", outDir, baseURL)))
+	for _, asset := range result.Assets {
+		pad := strings.Repeat(" ", width-lipgloss.Width(asset.Path))
+		fmt.Printf("  %s%s  %s\n", asset.Path, pad, ui.MutedStyle.Render(config.FormatSize(asset.Size)))
+	}
+	fmt.Println()
+}
+
+// printOutputSizes は出力ごとのサイズを前回のビルドとの差分・build.budgets の上限とともに表示する
+func printOutputSizes(result *plugin.BuildResult) {
+	if result.Previous != nil {
+		builtAt := result.Previous.BuiltAt
+		if t, err := time.Parse(time.RFC3339, builtAt); err == nil {
+			builtAt = t.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Printf("サイズ: %s\n", ui.MutedStyle.Render(fmt.Sprintf("（前回 v%s %s との差分）", result.Previous.Version, builtAt)))
 	} else {
-		fmt.Printf("  %s\n", ui.InfoStyle.Render(meta.PluginIDs.Prod))
+		fmt.Println("サイズ:")
+	}
+	for _, s := range result.Sizes {
+		delta := ui.MutedStyle.Render("新規")
+		if s.HasPrevious {
+			switch d := s.Delta(); {
+			case d > 0:
+				delta = ui.WarnStyle.Render("+" + config.FormatSize(d))
+			case d < 0:
+				delta = ui.SuccessStyle.Render("-" + config.FormatSize(-d))
+			default:
+				delta = ui.MutedStyle.Render("±0")
+			}
+		}
+		line := fmt.Sprintf("  %s%s%s", padCell(s.Name, 13), padCell(config.FormatSize(s.Size), 11), padCell(delta, 11))
+		switch {
+		case s.OverWarn():
+			line += ui.WarnStyle.Render(fmt.Sprintf("⚠ 上限 %s を超えています", config.FormatSize(s.Limits.Warn)))
+		case s.Limits.Error > 0:
+			line += ui.MutedStyle.Render("上限 " + config.FormatSize(s.Limits.Error))
+		case s.Limits.Warn > 0:
+			line += ui.MutedStyle.Render("上限 " + config.FormatSize(s.Limits.Warn))
+		}
+		fmt.Println(strings.TrimRight(line, " "))
 	}
+	fmt.Println()
+}
 
-	fmt.Printf("\n出力ファイル:\n")
-	fmt.Printf("  %s\n\n", ui.InfoStyle.Render(zipPath))
+// printBuildTimings は Build の工程ごとの所要時間を表示する
+func printBuildTimings(result *plugin.BuildResult) {
+	width := 0
+	for _, step := range result.Steps {
+		width = max(width, lipgloss.Width(step.Name))
+	}
 
-	return nil
+	fmt.Printf("所要時間: %s\n", ui.InfoStyle.Render(ui.FormatDuration(result.Total())))
+	for _, step := range result.Steps {
+		pad := strings.Repeat(" ", width-lipgloss.Width(step.Name))
+		fmt.Printf("  %s%s  %s\n", step.Name, pad, ui.FormatDuration(step.Duration))
+		for _, sub := range step.Sub {
+			line := fmt.Sprintf("%-8s%s", sub.Name, ui.FormatDuration(sub.Duration))
+			if sub.Cached {
+				line += "（キャッシュ）"
+			}
+			fmt.Printf("    %s\n", ui.MutedStyle.Render(line))
+		}
+	}
+	fmt.Println()
 }
 
 func loadBuildManifest(projectDir string) (map[string]interface{}, error) {
@@ -275,10 +469,19 @@ func askVersion(currentVersion string) (string, error) {
 	return answer, nil
 }
 
-func askBuildMode() (string, error) {
-	options := []huh.Option[string]{
-		huh.NewOption("本番ビルド (minify + console削除)", "prod"),
-		huh.NewOption("プレビルド (minifyなし + console残す + 名前に[開発]付与)", "pre"),
+// askBuildMode はビルドプロファイルを対話で選択する（prod・pre と build.profiles）
+func askBuildMode(cfg *config.Config) (string, error) {
+	var options []huh.Option[string]
+	for _, name := range cfg.ProfileNames() {
+		profile, err := cfg.Profile(name)
+		if err != nil {
+			return "", err
+		}
+		label := profile.Summary()
+		if name != "prod" && name != "pre" {
+			label = name + " (" + label + ")"
+		}
+		options = append(options, huh.NewOption(label, name))
 	}
 
 	var answer string
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kintone/kpdev/internal/config"
+	"github.com/kintone/kpdev/internal/plugin"
+	"github.com/kintone/kpdev/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "ビルドキャッシュを管理",
+	Long: `kpdev build / deploy が .kpdev/cache/ に保存するビルドキャッシュを管理します。
+
+src/・ロックファイル・vite.config.ts・ビルドの設定が変わっていなければ、
+バンドルした JS / CSS を再利用します。manifest.json の生成と署名は毎回行います。`,
+}
+
+var cacheCleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "ビルドキャッシュを削除",
+	RunE:  runCacheClean,
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "ビルドキャッシュの使用状況を表示",
+	RunE:  runCacheStats,
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheCleanCmd)
+	cacheCmd.AddCommand(cacheStatsCmd)
+}
+
+func runCacheClean(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	size, err := plugin.CleanCache(cwd)
+	if err != nil {
+		return fmt.Errorf("キャッシュの削除エラー: %w", err)
+	}
+	if size == 0 {
+		ui.Info("ビルドキャッシュはありません")
+		return nil
+	}
+	ui.Success(fmt.Sprintf("ビルドキャッシュを削除しました（%s）", config.FormatSize(size)))
+	return nil
+}
+
+func runCacheStats(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	entries, err := plugin.ListBundleCache(cwd)
+	if err != nil {
+		return fmt.Errorf("キャッシュの読み込みエラー: %w", err)
+	}
+
+	relDir, _ := filepath.Rel(cwd, plugin.GetCacheDir(cwd))
+	if len(entries) == 0 {
+		fmt.Printf("%s: %s\n", relDir, ui.MutedStyle.Render("キャッシュはありません"))
+		return nil
+	}
+
+	// バンドルごとに集計（ListBundleCache は最近使った順）
+	type bundleStats struct {
+		count  int
+		size   int64
+		usedAt time.Time
+	}
+	stats := map[string]*bundleStats{}
+	var order []string
+	var total int64
+	for _, e := range entries {
+		s, ok := stats[e.Bundle]
+		if !ok {
+			s = &bundleStats{usedAt: e.UsedAt}
+			stats[e.Bundle] = s
+			order = append(order, e.Bundle)
+		}
+		s.count++
+		s.size += e.Size
+		total += e.Size
+	}
+
+	fmt.Printf("%s: %d 件（%s）\n\n", relDir, len(entries), ui.InfoStyle.Render(config.FormatSize(total)))
+
+	header := "  " + padCell("バンドル", 10) + padCell("件数", 6) + padCell("サイズ", 10) + padCell("最終利用", 16)
+	fmt.Println(ui.MutedStyle.Render(header))
+	fmt.Println(ui.MutedStyle.Render("  " + strings.Repeat("─", lipgloss.Width(header)-2)))
+	for _, bundle := range order {
+		s := stats[bundle]
+		fmt.Printf("  %s%s%s%s\n", padCell(bundle, 10), padCell(fmt.Sprint(s.count), 6), padCell(config.FormatSize(s.size), 10), s.usedAt.Format("2006-01-02 15:04"))
+	}
+	return nil
+}
+
+// padCell は表示幅が width になるよう右に空白を詰める（全角文字は幅2として数える）
+func padCell(s string, width int) string {
+	if w := lipgloss.Width(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s + " "
+}
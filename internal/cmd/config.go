@@ -213,6 +213,9 @@ func showCurrentConfig(cfg *config.Config, projectDir string) {
 	// 開発環境
 	fmt.Printf("\n%s\n", ui.InfoStyle.Render("開発環境:"))
 	fmt.Printf("  ドメイン: %s\n", cfg.Kintone.Dev.Domain)
+	if cfg.Kintone.Dev.BaseURL != "" {
+		fmt.Printf("  接続先: %s\n", ui.WarnStyle.Render(cfg.Kintone.Dev.BaseURL))
+	}
 	if cfg.Kintone.Dev.Auth.Username != "" {
 		fmt.Printf("  ユーザー: %s\n", cfg.Kintone.Dev.Auth.Username)
 		fmt.Printf("  パスワード: %s\n", "********")
@@ -227,6 +230,9 @@ func showCurrentConfig(cfg *config.Config, projectDir string) {
 	} else {
 		for i, prod := range cfg.Kintone.Prod {
 			fmt.Printf("  [%d] %s (%s)\n", i+1, prod.Name, prod.Domain)
+			if prod.BaseURL != "" {
+				fmt.Printf("      接続先: %s\n", ui.WarnStyle.Render(prod.BaseURL))
+			}
 			if prod.Auth.Username != "" {
 				fmt.Printf("      ユーザー: %s\n", prod.Auth.Username)
 			}
@@ -951,8 +957,10 @@ func editEntryPoints(projectDir string, cfg *config.Config) error {
 	fmt.Printf("%s\n\n", ui.InfoStyle.Render("エントリーポイントの設定"))
 
 	fmt.Printf("現在のエントリーポイント:\n")
-	fmt.Printf("  main:   %s\n", ui.InfoStyle.Render(cfg.Dev.Entry.Main))
-	fmt.Printf("  config: %s\n\n", ui.InfoStyle.Render(cfg.Dev.Entry.Config))
+	fmt.Printf("  main:    %s\n", ui.InfoStyle.Render(cfg.Dev.Entry.Main))
+	fmt.Printf("  desktop: %s\n", entryOrShared(cfg.Dev.Entry.Desktop))
+	fmt.Printf("  mobile:  %s\n", entryOrShared(cfg.Dev.Entry.Mobile))
+	fmt.Printf("  config:  %s\n\n", ui.InfoStyle.Render(cfg.Dev.Entry.Config))
 
 	// mainエントリーポイント
 	mainEntry, err := askInput("main エントリーポイント", cfg.Dev.Entry.Main, true)
@@ -966,9 +974,35 @@ func editEntryPoints(projectDir string, cfg *config.Config) error {
 		return err
 	}
 
+	// desktop / mobile は空欄なら main を共有する
+	desktopEntry := cfg.Dev.Entry.Desktop
+	if cfg.Targets.Desktop {
+		desktopEntry, err = askInput("desktop エントリーポイント（空欄で main を共有）", cfg.Dev.Entry.Desktop, false)
+		if err != nil {
+			return err
+		}
+	}
+	mobileEntry := cfg.Dev.Entry.Mobile
+	if cfg.Targets.Mobile {
+		mobileEntry, err = askInput("mobile エントリーポイント（空欄で main を共有）", cfg.Dev.Entry.Mobile, false)
+		if err != nil {
+			return err
+		}
+	}
+
 	cfg.Dev.Entry.Main = mainEntry
 	cfg.Dev.Entry.Config = configEntry
+	cfg.Dev.Entry.Desktop = desktopEntry
+	cfg.Dev.Entry.Mobile = mobileEntry
 
 	ui.Success("エントリーポイントを更新しました")
 	return nil
 }
+
+// entryOrShared は個別のエントリーがなければ main を共有していることを表示する
+func entryOrShared(entry string) string {
+	if entry == "" {
+		return ui.MutedStyle.Render("（main を共有）")
+	}
+	return ui.InfoStyle.Render(entry)
+}
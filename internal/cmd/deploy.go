@@ -1,10 +1,15 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/huh"
 	"github.com/fatih/color"
@@ -18,10 +23,11 @@ import (
 )
 
 var (
-	flagDeployFile  string
-	flagDeployAll   bool
-	flagDeployForce bool
-	flagDeployMode  string
+	flagDeployFile    string
+	flagDeployAll     bool
+	flagDeployForce   bool
+	flagDeployMode    string
+	flagDeployNoCache bool
 )
 
 var deployCmd = &cobra.Command{
@@ -31,7 +37,8 @@ var deployCmd = &cobra.Command{
 
 モード:
   prod (デフォルト) - 本番用ビルド (minify + console削除)
-  pre              - プレビルド (minifyなし + console残す + 名前に[開発]付与)`,
+  pre              - プレビルド (minifyなし + console残す + 名前に[開発]付与)
+  その他           - .kpdev/config.json の build.profiles に定義したビルドプロファイル`,
 	RunE: runDeploy,
 }
 
@@ -41,7 +48,8 @@ func init() {
 	deployCmd.Flags().StringVar(&flagDeployFile, "file", "", "デプロイするZIPファイルのパス")
 	deployCmd.Flags().BoolVar(&flagDeployAll, "all", false, "全環境にデプロイ（対話スキップ）")
 	deployCmd.Flags().BoolVarP(&flagDeployForce, "force", "f", false, "確認ダイアログをスキップ（CI/CD向け）")
-	deployCmd.Flags().StringVar(&flagDeployMode, "mode", "prod", "ビルドモード (prod|pre)")
+	deployCmd.Flags().StringVar(&flagDeployMode, "mode", "prod", "ビルドモード (prod|pre|build.profiles のプロファイル名)")
+	deployCmd.Flags().BoolVar(&flagDeployNoCache, "no-cache", false, "ビルドキャッシュを使わずにすべてのバンドルをビルド")
 }
 
 func runDeploy(cmd *cobra.Command, args []string) error {
@@ -60,8 +68,11 @@ func runDeploy(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("設定ファイルが見つかりません。先に kpdev init を実行してください: %w", err)
 	}
 
-	// デプロイするZIPファイルを決定
-	var zipPath string
+	// デプロイするZIPファイルを決定（profile が nil でなければデプロイ先を選んでからビルドする）
+	var (
+		zipPath string
+		profile *config.ResolvedProfile
+	)
 	if flagDeployFile != "" {
 		// 指定されたファイルを使用
 		zipPath = flagDeployFile
@@ -103,11 +114,11 @@ func runDeploy(cmd *cobra.Command, args []string) error {
 		}
 
 		if needBuild {
-			// モードを決定
+			// モードを決定（ビルドはデプロイ先を選んでから環境の定数ごとに行う）
 			deployMode := flagDeployMode
 			if !cmd.Flags().Changed("mode") && !flagDeployForce {
 				// --mode が指定されていない場合は対話で選択
-				selectedMode, err := askDeployBuildMode()
+				selectedMode, err := askBuildMode(cfg)
 				if err != nil {
 					if errors.Is(err, huh.ErrUserAborted) {
 						return nil
@@ -117,27 +128,10 @@ func runDeploy(cmd *cobra.Command, args []string) error {
 				deployMode = selectedMode
 			}
 
-			// ビルドを実行
-			isPre := deployMode == "pre"
-			if isPre {
-				fmt.Printf("%s プレビルドを開始...\n\n", cyan("→"))
-			} else {
-				fmt.Printf("%s 本番ビルドを開始...\n\n", cyan("→"))
-			}
-			fmt.Printf("○ バンドル中...")
-
-			opts := &plugin.BuildOptions{
-				Mode:          deployMode,
-				Minify:        !isPre,
-				RemoveConsole: !isPre,
-			}
-
-			zipPath, err = plugin.Build(cwd, opts)
+			profile, err = cfg.Profile(deployMode)
 			if err != nil {
-				fmt.Println()
 				return err
 			}
-			fmt.Printf(" %s\n\n", green("✓"))
 		}
 	}
 
@@ -238,6 +232,23 @@ func runDeploy(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// デプロイする ZIP ごとに環境をまとめる
+	artifacts := groupDeployArtifacts(cfg, selectedIndices)
+	if profile == nil {
+		// 既存の ZIP はすべての環境に同じものをデプロイする
+		for _, a := range artifacts {
+			if len(a.env) > 0 {
+				ui.Warn("既存の ZIP をデプロイするため、環境の定数（env）は反映されません")
+				break
+			}
+		}
+		artifacts = []*deployArtifact{{zipPath: zipPath, indices: selectedIndices}}
+	} else {
+		if err := buildDeployArtifacts(cwd, cfg, profile, artifacts); err != nil {
+			return err
+		}
+	}
+
 	// メタデータとマニフェストを読み込み（プラグインIDとバージョン表示用）
 	meta, err := generator.LoadLoaderMeta(cwd)
 	if err != nil {
@@ -249,8 +260,17 @@ func runDeploy(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("manifest.json の読み込みに失敗しました: %w", err)
 	}
 
-	pluginID := meta.PluginIDs.Prod
-	pluginVersion := fmt.Sprintf("%v", manifest["version"])
+	deployState, err := config.LoadDeployState(cwd)
+	if err != nil {
+		return fmt.Errorf("%s の読み込みに失敗しました: %w", config.DeployStateFile, err)
+	}
+
+	// kintone の上限を超える ZIP はアップロードしても読み込めないため、どの環境にもデプロイしない
+	for _, artifact := range artifacts {
+		if err := plugin.CheckUploadLimits(artifact.zipPath); err != nil {
+			return err
+		}
+	}
 
 	fmt.Printf("%s プラグインをデプロイ中...\n\n", cyan("→"))
 
@@ -258,55 +278,91 @@ func runDeploy(cmd *cobra.Command, args []string) error {
 	successCount := 0
 	failCount := 0
 
-	for _, idx := range selectedIndices {
-		prod := cfg.Kintone.Prod[idx]
+	for _, artifact := range artifacts {
+		pluginID := meta.PluginIDs.Prod
+		pluginVersion := fmt.Sprintf("%v", manifest["version"])
+
+		// required_params はデプロイするZIPの manifest.json から取得する（--file の場合も正しく比較するため）
+		requiredParams := config.ManifestRequiredParams(manifest)
+		if src, err := plugin.OpenSource(artifact.zipPath); err == nil {
+			requiredParams = config.ManifestRequiredParams(src.Manifest)
+			pluginVersion = fmt.Sprintf("%v", src.Manifest["version"])
+			// プロファイルごとに署名鍵が異なるため、ZIP の公開鍵から求める
+			if src.PluginID != "" {
+				pluginID = src.PluginID
+			}
+		}
 
-		// 認証情報を取得
-		username := prod.Auth.Username
-		password := prod.Auth.Password
+		for _, idx := range artifact.indices {
+			prod := cfg.Kintone.Prod[idx]
 
-		// .envから取得を試みる（環境変数名は KPDEV_PROD_{NAME}_USERNAME 形式）
-		// TODO: 環境変数からの認証情報取得を実装
+			// 認証情報を取得
+			username := prod.Auth.Username
+			password := prod.Auth.Password
 
-		if username == "" || password == "" {
-			fmt.Printf("%s %s: 認証情報が設定されていません\n", red("✗"), prod.Name)
-			failCount++
-			continue
-		}
+			// .envから取得を試みる（環境変数名は KPDEV_PROD_{NAME}_USERNAME 形式）
+			// TODO: 環境変数からの認証情報取得を実装
 
-		var deployErr error
+			if username == "" || password == "" {
+				fmt.Printf("%s %s: 認証情報が設定されていません\n", red("✗"), prod.Name)
+				failCount++
+				continue
+			}
 
-		err := ui.SpinnerWithResult(fmt.Sprintf("%s にデプロイ中...", prod.Name), func() error {
-			// kintoneクライアントを作成
-			client := kintone.NewClient(prod.Domain, username, password)
+			warnAddedRequiredParams(prod, username, password, pluginID, deployState.Environments[prod.Name], requiredParams)
 
-			// ファイルをアップロード
-			fileKey, err := client.UploadFile(zipPath)
-			if err != nil {
-				deployErr = fmt.Errorf("アップロードエラー: %w", err)
-				return deployErr
-			}
+			var deployErr error
 
-			// プラグインをインポート
-			_, err = client.ImportPlugin(fileKey)
-			if err != nil {
-				deployErr = fmt.Errorf("インポートエラー: %w", err)
-				return deployErr
-			}
+			err := ui.SpinnerWithResult(fmt.Sprintf("%s にデプロイ中...", prod.Name), func() error {
+				// kintoneクライアントを作成
+				client := kintone.NewClientForURL(prod.URL(), username, password)
 
-			return nil
-		})
+				// ファイルをアップロード
+				fileKey, err := client.UploadFile(artifact.zipPath)
+				if err != nil {
+					deployErr = fmt.Errorf("アップロードエラー: %w", err)
+					return deployErr
+				}
+
+				// プラグインをインポート
+				_, err = client.ImportPlugin(fileKey)
+				if err != nil {
+					deployErr = fmt.Errorf("インポートエラー: %w", err)
+					return deployErr
+				}
+
+				return nil
+			})
+
+			if err != nil || deployErr != nil {
+				if deployErr != nil {
+					fmt.Printf("  %s\n", red(deployErr.Error()))
+				}
+				failCount++
+				continue
+			}
 
-		if err != nil || deployErr != nil {
-			if deployErr != nil {
-				fmt.Printf("  %s\n", red(deployErr.Error()))
+			fmt.Printf("  Plugin ID: %s (v%s)\n", pluginID, pluginVersion)
+			fmt.Printf("  ZIP: %s\n", filepath.Base(artifact.zipPath))
+			if len(artifact.env) > 0 {
+				fmt.Printf("  定数: %s\n", formatEnvConstants(artifact.env))
+			}
+			successCount++
+
+			deployState.Environments[prod.Name] = &config.DeployedPlugin{
+				Version:        pluginVersion,
+				RequiredParams: requiredParams,
+				DeployedAt:     time.Now().Format(time.RFC3339),
+				Artifact:       filepath.Base(artifact.zipPath),
+				Env:            artifact.env,
 			}
-			failCount++
-			continue
 		}
+	}
 
-		fmt.Printf("  Plugin ID: %s (v%s)\n", pluginID, pluginVersion)
-		successCount++
+	if successCount > 0 {
+		if err := deployState.Save(cwd); err != nil {
+			ui.Warn(fmt.Sprintf("%s の保存に失敗しました: %v", config.DeployStateFile, err))
+		}
 	}
 
 	fmt.Println()
@@ -321,6 +377,127 @@ func runDeploy(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// deployArtifact はデプロイする ZIP と、その ZIP をデプロイする環境
+type deployArtifact struct {
+	zipPath string
+	// env は ZIP に埋め込んだ環境の定数
+	env     map[string]interface{}
+	indices []int
+}
+
+// groupDeployArtifacts は選択された環境を定数の組み合わせごとにまとめる（選択順を保つ）
+func groupDeployArtifacts(cfg *config.Config, indices []int) []*deployArtifact {
+	var artifacts []*deployArtifact
+	byKey := map[string]*deployArtifact{}
+	for _, idx := range indices {
+		prod := cfg.Kintone.Prod[idx]
+		key := prod.EnvKey()
+		a, ok := byKey[key]
+		if !ok {
+			a = &deployArtifact{env: prod.Env}
+			byKey[key] = a
+			artifacts = append(artifacts, a)
+		}
+		a.indices = append(a.indices, idx)
+	}
+	return artifacts
+}
+
+// buildDeployArtifacts は定数の組み合わせごとにビルドする
+// 定数が環境によって異なる場合は、ZIP のファイル名にデプロイ先の環境名を付ける
+func buildDeployArtifacts(projectDir string, cfg *config.Config, profile *config.ResolvedProfile, artifacts []*deployArtifact) error {
+	green := color.New(color.FgGreen).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	switch profile.Name {
+	case "prod":
+		fmt.Printf("%s 本番ビルドを開始...\n\n", cyan("→"))
+	case "pre":
+		fmt.Printf("%s プレビルドを開始...\n\n", cyan("→"))
+	default:
+		fmt.Printf("%s %s ビルドを開始...\n\n", cyan("→"), profile.Name)
+	}
+
+	for i, a := range artifacts {
+		opts := &plugin.BuildOptions{
+			Profile:  profile,
+			NoCache:  flagDeployNoCache,
+			Env:      a.env,
+			KeepZips: i > 0,
+		}
+		if len(artifacts) > 1 {
+			var names []string
+			for _, idx := range a.indices {
+				names = append(names, cfg.Kintone.Prod[idx].Name)
+			}
+			opts.Environment = strings.Join(names, "_")
+			fmt.Printf("○ バンドル中 (%s)...", strings.Join(names, ", "))
+		} else {
+			fmt.Printf("○ バンドル中...")
+		}
+
+		result, err := plugin.Build(projectDir, opts)
+		if err != nil {
+			fmt.Println()
+			return err
+		}
+		a.zipPath = result.ZipPath
+		fmt.Printf(" %s\n", green("✓"))
+		for _, size := range result.Sizes {
+			if size.OverWarn() {
+				ui.Warn(fmt.Sprintf("%s が build.budgets の上限 %s を超えています（%s）", size.Name, config.FormatSize(size.Limits.Warn), config.FormatSize(size.Size)))
+			}
+		}
+	}
+	fmt.Println()
+	return nil
+}
+
+// formatEnvConstants は環境の定数を KEY=値 の形式で返す（キーの昇順）
+func formatEnvConstants(env map[string]interface{}) string {
+	var parts []string
+	for _, key := range slices.Sorted(maps.Keys(env)) {
+		value, _ := json.Marshal(env[key])
+		parts = append(parts, key+"="+string(value))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// warnAddedRequiredParams は前回のデプロイから required_params が増えている場合に、
+// 設定し直しが必要になるアプリを警告する（取得に失敗してもデプロイは続行する）
+func warnAddedRequiredParams(prod config.ProdEnvConfig, username, password, pluginID string, previous *config.DeployedPlugin, requiredParams []string) {
+	if len(requiredParams) == 0 {
+		return
+	}
+	if previous == nil {
+		fmt.Println(ui.MutedStyle.Render(fmt.Sprintf("  %s: 前回のデプロイ記録がないため、required_params の追加を確認できません（kpdev usage で使用中のアプリを確認できます）", prod.Name)))
+		return
+	}
+
+	var added []string
+	for _, p := range requiredParams {
+		if !slices.Contains(previous.RequiredParams, p) {
+			added = append(added, p)
+		}
+	}
+	if len(added) == 0 {
+		return
+	}
+
+	client := kintone.NewClientForURL(prod.URL(), username, password)
+	apps, err := pluginUsage(client, pluginID)
+	if err != nil {
+		ui.Warn(fmt.Sprintf("%s: required_params（%s）が追加されていますが、使用中のアプリを取得できませんでした: %v", prod.Name, strings.Join(added, ", "), err))
+		return
+	}
+	if len(apps) == 0 {
+		return
+	}
+
+	ui.Warn(fmt.Sprintf("%s: v%s から required_params（%s）が追加されています。次のアプリはプラグインの設定し直しが必要です", prod.Name, previous.Version, strings.Join(added, ", ")))
+	printAppUsage(apps)
+}
+
 // findZipFiles は指定ディレクトリ内のZIPファイルを検索して返す
 func findZipFiles(dir string) []string {
 	var files []string
@@ -366,25 +543,3 @@ func findLatestZipFile(dir string, files []string) string {
 
 	return latestFile
 }
-
-func askDeployBuildMode() (string, error) {
-	options := []huh.Option[string]{
-		huh.NewOption("本番ビルド (minify + console削除)", "prod"),
-		huh.NewOption("プレビルド (minifyなし + console残す + 名前に[開発]付与)", "pre"),
-	}
-
-	var answer string
-	err := huh.NewForm(
-		huh.NewGroup(
-			huh.NewSelect[string]().
-				Title("ビルドモードを選択").
-				Options(options...).
-				Value(&answer),
-		),
-	).WithTheme(huh.ThemeCatppuccin()).Run()
-	if err != nil {
-		return "", err
-	}
-
-	return answer, nil
-}
@@ -5,9 +5,10 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"os/signal"
+	"os/user"
 	"path/filepath"
-	"syscall"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
@@ -23,6 +24,10 @@ var (
 	flagSkipDeploy bool
 	flagNoBrowser  bool
 	flagDevForce   bool
+
+	flagForwardConsole bool
+
+	flagDevApps []string
 )
 
 var devCmd = &cobra.Command{
@@ -38,6 +43,8 @@ func init() {
 	devCmd.Flags().BoolVar(&flagSkipDeploy, "skip-deploy", false, "ローダープラグインのデプロイをスキップ")
 	devCmd.Flags().BoolVar(&flagNoBrowser, "no-browser", false, "ブラウザを自動で開かない")
 	devCmd.Flags().BoolVarP(&flagDevForce, "force", "f", false, "確認ダイアログをスキップ（CI/CD向け）")
+	devCmd.Flags().BoolVar(&flagForwardConsole, "forward-console", false, "プラグイン内の console.* 出力もターミナルに転送")
+	devCmd.Flags().StringSliceVar(&flagDevApps, "app", nil, "開発用プラグインを追加するアプリID（カンマ区切りで複数指定可）")
 }
 
 func runDev(cmd *cobra.Command, args []string) error {
@@ -58,32 +65,42 @@ func runDev(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("loader.meta.json が見つかりません。先に kpdev init を実行してください: %w", err)
 	}
 
+	// --app の指定がなければ kpdev app create で作成したテストアプリに追加する
+	devApps := flagDevApps
+	if len(devApps) == 0 && cfg.Kintone.Dev.TestApp != nil {
+		devApps = []string{cfg.Kintone.Dev.TestApp.ID}
+	}
+	for _, app := range devApps {
+		if _, err := strconv.Atoi(app); err != nil {
+			return fmt.Errorf("アプリIDは数値で指定してください: %s", app)
+		}
+	}
+
+	if err := cfg.CheckEntries(cwd); err != nil {
+		ui.Warn(err.Error())
+		fmt.Println()
+	}
+
+	if meta.SchemaVersion < generator.LoaderSchemaVersion {
+		ui.Warn("開発用ローダーが古い形式です。kpdev migrate で更新するとブラウザのエラーがターミナルに表示されます")
+		fmt.Println()
+	}
+
 	// プラグインをデプロイ
 	if !flagSkipDeploy {
 		ui.Info("開発用プラグインをkintoneにデプロイ中...")
 		fmt.Println()
 
-		// 認証情報を取得
-		username := cfg.Kintone.Dev.Auth.Username
-		password := cfg.Kintone.Dev.Auth.Password
-
-		// .envから取得を試みる
-		if envCfg, err := config.LoadEnv(cwd); err == nil && envCfg.HasAuth() {
-			username = envCfg.Username
-			password = envCfg.Password
-		}
-
-		if username == "" || password == "" {
-			return fmt.Errorf("認証情報が設定されていません")
+		client, err := newDevClient(cwd, cfg)
+		if err != nil {
+			return err
 		}
 
-		client := kintone.NewClient(cfg.Kintone.Dev.Domain, username, password)
-
 		// プラグインZIPを作成
 		var zipPath string
 		err = ui.SpinnerWithResult("プラグインをパッケージング中...", func() error {
 			var packErr error
-			zipPath, packErr = plugin.PackageDevPlugin(cwd)
+			zipPath, packErr = plugin.PackageDevPlugin(cwd, devLoaderOwner(cwd, cfg))
 			return packErr
 		})
 		if err != nil {
@@ -113,6 +130,16 @@ func runDev(cmd *cobra.Command, args []string) error {
 		fmt.Println()
 	}
 
+	// 指定したアプリに開発用プラグインを追加（途中で失敗しても追加済みのアプリは終了時に案内する）
+	var attachedApps []string
+	defer func() { printDetachGuide(cfg, attachedApps) }()
+	if len(devApps) > 0 {
+		attachedApps, err = attachDevLoader(cwd, cfg, meta.PluginIDs.Dev, devApps)
+		if err != nil {
+			return err
+		}
+	}
+
 	// プラグイン情報を表示
 	fmt.Printf("Plugin ID:\n")
 	fmt.Printf("  %s\n", ui.InfoStyle.Render(meta.PluginIDs.Dev))
@@ -122,8 +149,26 @@ func runDev(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  %s\n", ui.InfoStyle.Render(meta.Dev.Origin))
 	fmt.Println()
 
+	fmt.Printf("設定画面プレビュー:\n")
+	fmt.Printf("  %s\n", ui.InfoStyle.Render(meta.Dev.Origin+"/__kpdev/config-preview"))
+	fmt.Println()
+
+	if len(devApps) > 0 {
+		fmt.Printf("アプリ:\n")
+		for _, app := range devApps {
+			fmt.Printf("  %s\n", ui.InfoStyle.Render(devAppURL(cfg, app)))
+		}
+		fmt.Println()
+	}
+
 	fmt.Printf("エントリー:\n")
 	fmt.Printf("  main:   %s\n", meta.Entries.Main)
+	if cfg.Dev.Entry.Desktop != "" {
+		fmt.Printf("  desktop: %s\n", cfg.Dev.Entry.Desktop)
+	}
+	if cfg.Dev.Entry.Mobile != "" {
+		fmt.Printf("  mobile: %s\n", cfg.Dev.Entry.Mobile)
+	}
 	fmt.Printf("  config: %s\n", meta.Entries.Config)
 	fmt.Println()
 
@@ -137,13 +182,55 @@ func runDev(cmd *cobra.Command, args []string) error {
 		fmt.Println()
 	}
 
+	// kintone のURL（o キーで開く）
+	kintoneURL := meta.Dev.Origin
+	if cfg.Kintone.Dev.Domain != "" || cfg.Kintone.Dev.BaseURL != "" {
+		kintoneURL = cfg.Kintone.Dev.URL() + "/k/"
+	}
+	if len(devApps) > 0 {
+		kintoneURL = devAppURL(cfg, devApps[0])
+	}
+
+	// ローダーの再デプロイ（r キーと config.html 監視から呼ばれる）
+	var deployMu sync.Mutex
+	var dash *ui.DevDashboard
+	redeploy := func(reason string) {
+		deployMu.Lock()
+		defer deployMu.Unlock()
+
+		dash.LoaderStatus(ui.DevStateBusy, reason+"再デプロイ中...")
+		if err := redeployDevLoader(cwd, cfg); err != nil {
+			dash.LoaderStatus(ui.DevStateError, "再デプロイ失敗")
+			dash.Error(err.Error())
+			return
+		}
+		dash.LoaderStatus(ui.DevStateOK, "再デプロイ完了")
+	}
+
+	dash = ui.NewDevDashboard(ui.DevDashboardOptions{
+		PluginID:   meta.PluginIDs.Dev,
+		Origin:     meta.Dev.Origin,
+		OnRedeploy: func() { redeploy("") },
+		OnOpen:     func() { openBrowser(kintoneURL) },
+	})
+
+	dash.Log("設定画面プレビュー: " + meta.Dev.Origin + "/__kpdev/config-preview")
+
+	if flagSkipDeploy {
+		dash.LoaderStatus(ui.DevStateSkipped, "デプロイをスキップ")
+	} else {
+		dash.LoaderStatus(ui.DevStateOK, "デプロイ済み")
+	}
+
 	// config.html監視を開始
 	configHTMLPath := filepath.Join(cwd, "src", "config", "index.html")
-	go watchConfigHTML(cwd, configHTMLPath, cfg)
+	go watchConfigHTML(cwd, configHTMLPath, dash, redeploy)
 
 	// Vite dev server を起動
-	ui.Info("Dev server を起動中...")
-	fmt.Println()
+	if !dash.Interactive() {
+		ui.Info("Dev server を起動中...")
+		fmt.Println()
+	}
 
 	viteConfigPath := filepath.Join(config.GetConfigDir(cwd), "vite.config.ts")
 
@@ -152,52 +239,204 @@ func runDev(cmd *cobra.Command, args []string) error {
 
 	viteCmd := exec.CommandContext(ctx, "npx", "vite", "--config", viteConfigPath)
 	viteCmd.Dir = cwd
-	viteCmd.Stdout = os.Stdout
-	viteCmd.Stderr = os.Stderr
-	viteCmd.Stdin = os.Stdin
-
-	// シグナルハンドリング
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	viteCmd.Stdout = dash.ViteWriter(false)
+	viteCmd.Stderr = dash.ViteWriter(true)
+	viteCmd.Env = append(os.Environ(), generator.ViteEntriesEnv(cfg))
+	if flagForwardConsole {
+		viteCmd.Env = append(viteCmd.Env, "KPDEV_FORWARD_CONSOLE=1")
+	}
+	if !dash.Interactive() {
+		// ダッシュボードがキー入力を使うため、プレーン出力時のみ Vite に stdin を渡す
+		viteCmd.Stdin = os.Stdin
+	}
 
 	if err := viteCmd.Start(); err != nil {
 		return fmt.Errorf("Vite起動エラー: %w", err)
 	}
+	dash.ServerStatus(ui.DevStateBusy, "起動中...")
 
 	// ブラウザを開く
 	if !flagNoBrowser {
 		go func() {
 			// Viteが起動するまで少し待つ
 			time.Sleep(1 * time.Second)
-			openBrowser(meta.Dev.Origin)
+			if len(devApps) > 0 {
+				openBrowser(kintoneURL)
+			} else {
+				openBrowser(meta.Dev.Origin)
+			}
 		}()
 	}
 
-	// シグナルまたはプロセス終了を待つ
-	terminated := make(chan struct{})
+	// Vite が終了したらダッシュボードも閉じる
+	viteDone := make(chan error, 1)
 	go func() {
-		<-sigChan
-		signal.Stop(sigChan)
-		cancel()
-		close(terminated)
+		viteDone <- viteCmd.Wait()
+		dash.Stop()
 	}()
 
-	waitErr := viteCmd.Wait()
+	// ユーザーの終了操作（q / Ctrl+C）または Vite の終了を待つ
+	userQuit, runErr := dash.Run()
+	cancel()
+	waitErr := <-viteDone
 
-	// Ctrl+C による終了の場合はエラーなしで終了
-	select {
-	case <-terminated:
+	// ユーザー操作による終了の場合はエラーなしで終了
+	if userQuit {
 		fmt.Println()
 		return nil
-	default:
-		return waitErr
 	}
+	if runErr != nil {
+		return runErr
+	}
+	return waitErr
+}
+
+// devCredentials は開発環境の認証情報を取得する
+// 認証情報は .env → config.json の順で取得する
+func devCredentials(projectDir string, cfg *config.Config) (string, string) {
+	username := cfg.Kintone.Dev.Auth.Username
+	password := cfg.Kintone.Dev.Auth.Password
+
+	// .envから取得を試みる
+	if envCfg, err := config.LoadEnv(projectDir); err == nil && envCfg.HasAuth() {
+		username = envCfg.Username
+		password = envCfg.Password
+	}
+
+	return username, password
+}
+
+// newDevClient は開発環境の kintone クライアントを作成する
+func newDevClient(projectDir string, cfg *config.Config) (*kintone.Client, error) {
+	username, password := devCredentials(projectDir, cfg)
+	if username == "" || password == "" {
+		return nil, fmt.Errorf("認証情報が設定されていません")
+	}
+
+	return kintone.NewClientForURL(cfg.Kintone.Dev.URL(), username, password), nil
+}
+
+// newEnvClient は --env で指定した環境の kintone クライアントを作成する
+// "dev" は開発環境、それ以外は本番環境の name として扱う
+func newEnvClient(projectDir string, cfg *config.Config, env string) (*kintone.Client, error) {
+	if env == "" || env == "dev" {
+		return newDevClient(projectDir, cfg)
+	}
+
+	for _, prod := range cfg.Kintone.Prod {
+		if prod.Name != env {
+			continue
+		}
+		if prod.Auth.Username == "" || prod.Auth.Password == "" {
+			return nil, fmt.Errorf("%s: 認証情報が設定されていません", prod.Name)
+		}
+		return kintone.NewClientForURL(prod.URL(), prod.Auth.Username, prod.Auth.Password), nil
+	}
+
+	return nil, fmt.Errorf("環境が見つかりません: %s（dev または本番環境の name を指定してください）", env)
+}
+
+// devAppURL はアプリのURLを返す
+func devAppURL(cfg *config.Config, appID string) string {
+	return cfg.Kintone.Dev.URL() + "/k/" + appID + "/"
+}
+
+// attachDevLoader はアプリに開発用プラグインを追加して設定を反映する
+// 新たに追加したアプリのIDを返す（追加済みのアプリは含めない）
+func attachDevLoader(projectDir string, cfg *config.Config, pluginID string, apps []string) ([]string, error) {
+	client, err := newDevClient(projectDir, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var attached []string
+	for _, app := range apps {
+		plugins, err := client.GetPreviewAppPlugins(app)
+		if err != nil {
+			return attached, fmt.Errorf("アプリ %s のプラグイン取得エラー: %w", app, err)
+		}
+
+		found := false
+		for _, p := range plugins {
+			if p.ID == pluginID {
+				found = true
+				break
+			}
+		}
+		if found {
+			ui.Success(fmt.Sprintf("アプリ %s には開発用プラグインが追加済みです", app))
+			continue
+		}
+
+		err = ui.SpinnerWithResult(fmt.Sprintf("アプリ %s に開発用プラグインを追加中...", app), func() error {
+			if err := client.AddAppPlugins(app, []string{pluginID}); err != nil {
+				return err
+			}
+			return client.DeployApp(app)
+		})
+		if err != nil {
+			return attached, fmt.Errorf("アプリ %s へのプラグイン追加エラー: %w", app, err)
+		}
+		attached = append(attached, app)
+	}
+	fmt.Println()
+
+	return attached, nil
+}
+
+// printDetachGuide は今回開発用プラグインを追加したアプリを表示し、手動で外すよう案内する
+// kintone の REST API ではアプリからプラグインを外せないため、kpdev は外さない
+func printDetachGuide(cfg *config.Config, apps []string) {
+	if len(apps) == 0 {
+		return
+	}
+
+	fmt.Println()
+	ui.Info("次のアプリに開発用プラグインを追加しました。不要になったら、アプリの設定の「プラグイン」から削除してアプリを更新してください")
+	for _, app := range apps {
+		fmt.Printf("  %s\n", ui.MutedStyle.Render(devAppURL(cfg, app)))
+	}
+}
+
+// devLoaderOwner はローダーの管理者名（デプロイするユーザー）を返す
+func devLoaderOwner(projectDir string, cfg *config.Config) string {
+	if username, _ := devCredentials(projectDir, cfg); username != "" {
+		return username
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
 }
 
-func watchConfigHTML(projectDir, configHTMLPath string, cfg *config.Config) {
+// redeployDevLoader は開発用プラグインをパッケージングして再デプロイする
+func redeployDevLoader(projectDir string, cfg *config.Config) error {
+	client, err := newDevClient(projectDir, cfg)
+	if err != nil {
+		return err
+	}
+
+	zipPath, err := plugin.PackageDevPlugin(projectDir, devLoaderOwner(projectDir, cfg))
+	if err != nil {
+		return fmt.Errorf("パッケージングエラー: %w", err)
+	}
+
+	fileKey, err := client.UploadFile(zipPath)
+	if err != nil {
+		return fmt.Errorf("アップロードエラー: %w", err)
+	}
+
+	if _, err := client.ImportPlugin(fileKey); err != nil {
+		return fmt.Errorf("インポートエラー: %w", err)
+	}
+
+	return nil
+}
+
+func watchConfigHTML(projectDir, configHTMLPath string, dash *ui.DevDashboard, redeploy func(reason string)) {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
-		fmt.Printf("  ファイル監視の初期化に失敗: %v\n", err)
+		dash.Error(fmt.Sprintf("ファイル監視の初期化に失敗: %v", err))
 		return
 	}
 	defer watcher.Close()
@@ -205,11 +444,11 @@ func watchConfigHTML(projectDir, configHTMLPath string, cfg *config.Config) {
 	// 親ディレクトリを監視（ファイルが存在しない場合も考慮）
 	configDir := filepath.Dir(configHTMLPath)
 	if err := watcher.Add(configDir); err != nil {
-		fmt.Printf("  %s の監視に失敗: %v\n", configDir, err)
+		dash.Error(fmt.Sprintf("%s の監視に失敗: %v", configDir, err))
 		return
 	}
 
-	fmt.Printf("  %s\n", ui.MutedStyle.Render("config.html を監視中: "+configHTMLPath))
+	dash.Log(ui.MutedStyle.Render("config.html を監視中: " + configHTMLPath))
 
 	// 処理中フラグ
 	processing := false
@@ -239,65 +478,23 @@ func watchConfigHTML(projectDir, configHTMLPath string, cfg *config.Config) {
 			// 連続イベントをまとめるための短い待機
 			time.Sleep(100 * time.Millisecond)
 
-			fmt.Printf("\n%s config.html が変更されました。プラグインを再デプロイ中...\n", ui.WarnStyle.Render("->"))
-
 			// dev-plugin/config.html を更新
 			devPluginDir := filepath.Join(config.GetConfigDir(projectDir), "managed", "dev-plugin")
 			srcHTML, err := os.ReadFile(configHTMLPath)
 			if err != nil {
-				fmt.Printf("  %s HTMLの読み込みに失敗: %v\n", ui.WarnStyle.Render("!"), err)
+				dash.Error(fmt.Sprintf("HTMLの読み込みに失敗: %v", err))
 				processing = false
 				continue
 			}
 
 			// config.html をそのままコピー
 			if err := os.WriteFile(filepath.Join(devPluginDir, "config.html"), srcHTML, 0644); err != nil {
-				fmt.Printf("  %s HTMLの書き込みに失敗: %v\n", ui.WarnStyle.Render("!"), err)
+				dash.Error(fmt.Sprintf("HTMLの書き込みに失敗: %v", err))
 				processing = false
 				continue
 			}
 
-			// プラグインをパッケージング
-			fmt.Printf("  ○ プラグインをパッケージング中...")
-			zipPath, err := plugin.PackageDevPlugin(projectDir)
-			if err != nil {
-				fmt.Printf(" %s\n", ui.WarnStyle.Render("x"))
-				fmt.Printf("    %v\n", err)
-				processing = false
-				continue
-			}
-			fmt.Printf(" %s\n", ui.SuccessStyle.Render(ui.IconSuccess))
-
-			// kintoneにアップロード
-			fmt.Printf("  ○ プラグインをアップロード中...")
-
-			username := cfg.Kintone.Dev.Auth.Username
-			password := cfg.Kintone.Dev.Auth.Password
-			if envCfg, err := config.LoadEnv(projectDir); err == nil && envCfg.HasAuth() {
-				username = envCfg.Username
-				password = envCfg.Password
-			}
-
-			client := kintone.NewClient(cfg.Kintone.Dev.Domain, username, password)
-			fileKey, err := client.UploadFile(zipPath)
-			if err != nil {
-				fmt.Printf(" %s\n", ui.WarnStyle.Render("x"))
-				fmt.Printf("    %v\n", err)
-				processing = false
-				continue
-			}
-			fmt.Printf(" %s\n", ui.SuccessStyle.Render(ui.IconSuccess))
-
-			// プラグインをインポート
-			fmt.Printf("  ○ プラグインをインポート中...")
-			_, err = client.ImportPlugin(fileKey)
-			if err != nil {
-				fmt.Printf(" %s\n", ui.WarnStyle.Render("x"))
-				fmt.Printf("    %v\n", err)
-				processing = false
-				continue
-			}
-			fmt.Printf(" %s\n", ui.SuccessStyle.Render(ui.IconSuccess))
+			redeploy("config.html の変更を検知。")
 
 			// kintone 側の反映を待つ
 			time.Sleep(500 * time.Millisecond)
@@ -318,15 +515,13 @@ func watchConfigHTML(projectDir, configHTMLPath string, cfg *config.Config) {
 				os.Chtimes(entryFile, now, now)
 			}
 
-			fmt.Printf("%s 再デプロイ完了。自動リロードします。\n", ui.InfoStyle.Render("->"))
-
 			processing = false
 
 		case err, ok := <-watcher.Errors:
 			if !ok {
 				return
 			}
-			fmt.Printf("ファイル監視エラー: %v\n", err)
+			dash.Error(fmt.Sprintf("ファイル監視エラー: %v", err))
 		}
 	}
 }
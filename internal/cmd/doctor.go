@@ -197,7 +197,7 @@ func checkConfigFiles(projectDir string) []checkResult {
 		} else {
 			// 必須フィールドの確認
 			issues := []string{}
-			if cfg.Kintone.Dev.Domain == "" {
+			if cfg.Kintone.Dev.Domain == "" && cfg.Kintone.Dev.BaseURL == "" {
 				issues = append(issues, "開発ドメイン未設定")
 			}
 			if cfg.Dev.Entry.Main == "" {
@@ -0,0 +1,465 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/kintone/kpdev/internal/config"
+	"github.com/kintone/kpdev/internal/kintone"
+	"github.com/kintone/kpdev/internal/prompt"
+	"github.com/kintone/kpdev/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// fixturesDir はフィクスチャの既定の出力先
+const fixturesDir = "fixtures"
+
+var (
+	flagFixturesApp     string
+	flagFixturesEnv     string
+	flagFixturesQuery   string
+	flagFixturesOut     string
+	flagFixturesReplace bool
+	flagFixturesDryRun  bool
+	flagFixturesForce   bool
+)
+
+var fixturesCmd = &cobra.Command{
+	Use:   "fixtures",
+	Short: "アプリのレコードをフィクスチャとして書き出し・登録",
+}
+
+var fixturesExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "アプリのレコードをフィクスチャに書き出す",
+	Long: `アプリのレコードをカーソルAPIで取得し、フィクスチャ（JSON）に書き出します。
+
+添付ファイルはフィクスチャと同じ場所の <ファイル名>.files/ にダウンロードします。
+--app を省略すると kpdev app create で作成したテストアプリが対象になります。`,
+	Example: `  kpdev fixtures export --app 123
+  kpdev fixtures export --app 123 --query 'ステータス in ("完了")' -o .kpdev/fixtures/done.json
+  kpdev fixtures export --app 123 --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runFixturesExport,
+}
+
+var fixturesImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "フィクスチャのレコードをアプリに登録する",
+	Long: `フィクスチャのレコードをアプリに100件ずつ登録します。
+
+添付ファイルはアップロードしてから登録します。--replace を指定すると既存のレコードを
+すべて削除してから登録するため、開発用アプリを決まった状態に戻せます。
+--app を省略すると kpdev app create で作成したテストアプリが対象になります。
+本番環境（--env に本番環境の name）に登録する場合は確認します（--force で省略）。`,
+	Example: `  kpdev fixtures import .kpdev/fixtures/app-123.json --app 123
+  kpdev fixtures import .kpdev/fixtures/app-123.json --app 123 --replace
+  kpdev fixtures import .kpdev/fixtures/app-123.json --app 123 --replace --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFixturesImport,
+}
+
+func init() {
+	rootCmd.AddCommand(fixturesCmd)
+	fixturesCmd.AddCommand(fixturesExportCmd)
+	fixturesCmd.AddCommand(fixturesImportCmd)
+
+	for _, c := range []*cobra.Command{fixturesExportCmd, fixturesImportCmd} {
+		c.Flags().StringVar(&flagFixturesApp, "app", "", "アプリID（省略時はテストアプリ）")
+		c.Flags().StringVar(&flagFixturesEnv, "env", "dev", "対象の環境（dev または本番環境の name）")
+		c.Flags().BoolVar(&flagFixturesDryRun, "dry-run", false, "書き込みを行わず、実行内容だけを表示する")
+	}
+	fixturesExportCmd.Flags().StringVar(&flagFixturesQuery, "query", "", "取得するレコードの条件（kintone のクエリ）")
+	fixturesExportCmd.Flags().StringVarP(&flagFixturesOut, "out", "o", "", "出力先（デフォルト: .kpdev/fixtures/app-<id>.json）")
+	fixturesImportCmd.Flags().BoolVar(&flagFixturesReplace, "replace", false, "登録前に既存のレコードをすべて削除する")
+	fixturesImportCmd.Flags().BoolVarP(&flagFixturesForce, "force", "f", false, "本番環境に登録する場合の確認ダイアログをスキップ（CI/CD向け）")
+}
+
+// fixtureFile はフィクスチャファイルの形式（kpdev sandbox のフィクスチャと互換）
+type fixtureFile struct {
+	App struct {
+		ID string `json:"id"`
+	} `json:"app"`
+	Query   string           `json:"query,omitempty"`
+	Records []kintone.Record `json:"records"`
+}
+
+// orderByPattern はクエリに並び順の指定があるかを判定する
+var orderByPattern = regexp.MustCompile(`(?i)\border\s+by\b`)
+
+// fixtureAppID は --app の値、未指定ならテストアプリのIDを返す
+func fixtureAppID(cfg *config.Config) (string, error) {
+	if flagFixturesApp != "" {
+		return flagFixturesApp, nil
+	}
+	if cfg.Kintone.Dev.TestApp != nil && (flagFixturesEnv == "" || flagFixturesEnv == "dev") {
+		return cfg.Kintone.Dev.TestApp.ID, nil
+	}
+	return "", fmt.Errorf("--app でアプリIDを指定してください")
+}
+
+// fixtureFilesDir はフィクスチャの添付ファイルを置くディレクトリを返す
+func fixtureFilesDir(fixturePath string) string {
+	return strings.TrimSuffix(fixturePath, filepath.Ext(fixturePath)) + ".files"
+}
+
+func runFixturesExport(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		return fmt.Errorf("設定ファイルが見つかりません。先に kpdev init を実行してください: %w", err)
+	}
+
+	appID, err := fixtureAppID(cfg)
+	if err != nil {
+		return err
+	}
+
+	outPath := flagFixturesOut
+	if outPath == "" {
+		outPath = filepath.Join(config.ConfigDir, fixturesDir, "app-"+appID+".json")
+	}
+	if !filepath.IsAbs(outPath) {
+		outPath = filepath.Join(cwd, outPath)
+	}
+	relOut, _ := filepath.Rel(cwd, outPath)
+
+	// フィクスチャの差分が安定するよう、並び順の指定がなければレコードID順にする
+	query := strings.TrimSpace(flagFixturesQuery)
+	if !orderByPattern.MatchString(query) {
+		query = strings.TrimSpace(query + " order by $id asc")
+	}
+
+	client, err := newEnvClient(cwd, cfg, flagFixturesEnv)
+	if err != nil {
+		return err
+	}
+
+	var records []kintone.Record
+	err = ui.SpinnerWithResult(fmt.Sprintf("アプリ %s のレコードを取得中...", appID), func() error {
+		var fetchErr error
+		records, fetchErr = client.GetRecordsByCursor(appID, query, nil)
+		return fetchErr
+	})
+	if err != nil {
+		return fmt.Errorf("レコード取得エラー: %w", err)
+	}
+
+	// 添付ファイルは <出力先>.files/<レコードID>/<フィールドコード>/<番号>/<ファイル名> に保存し、値を相対パスに置き換える
+	// （同じレコードに同名のファイルがあっても上書きしないよう、フィールド・テーブルの行をまたいで番号を振る。
+	// アップロード時のファイル名に使うため、ファイル名はそのまま残す）
+	filesDir := fixtureFilesDir(outPath)
+	type download struct {
+		fileKey string
+		path    string
+	}
+	var downloads []download
+	for _, record := range records {
+		delete(record, "$revision")
+		recordID := fixtureRecordID(record)
+		counts := map[string]int{}
+
+		err := kintone.MapFileFields(record, func(code string, files []interface{}) ([]interface{}, error) {
+			mapped := make([]interface{}, 0, len(files))
+			for _, f := range files {
+				file, _ := f.(map[string]interface{})
+				fileKey, _ := file["fileKey"].(string)
+				name, _ := file["name"].(string)
+				if fileKey == "" || name == "" {
+					return nil, fmt.Errorf("レコード %s のフィールド %s: 添付ファイルの fileKey がありません", recordID, code)
+				}
+
+				index := counts[code]
+				counts[code]++
+				path := filepath.Join(filesDir, recordID, code, fmt.Sprint(index), filepath.Base(name))
+				rel, _ := filepath.Rel(filepath.Dir(outPath), path)
+				downloads = append(downloads, download{fileKey: fileKey, path: path})
+				mapped = append(mapped, map[string]interface{}{
+					"name":        name,
+					"contentType": file["contentType"],
+					"size":        file["size"],
+					"path":        filepath.ToSlash(rel),
+				})
+			}
+			return mapped, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if flagFixturesDryRun {
+		fmt.Println()
+		ui.Info("ドライラン: ファイルは書き込みません")
+		fmt.Printf("  レコード:     %d件\n", len(records))
+		fmt.Printf("  添付ファイル: %d件\n", len(downloads))
+		fmt.Printf("  出力先:       %s\n", ui.InfoStyle.Render(relOut))
+		return nil
+	}
+
+	if len(downloads) > 0 {
+		// 前回の書き出しで残った添付ファイルを消してから保存する
+		if err := os.RemoveAll(filesDir); err != nil {
+			return err
+		}
+		err = ui.SpinnerWithResult(fmt.Sprintf("添付ファイルをダウンロード中（%d件）...", len(downloads)), func() error {
+			for _, d := range downloads {
+				data, err := client.DownloadFile(d.fileKey)
+				if err != nil {
+					return fmt.Errorf("%s: %w", filepath.Base(d.path), err)
+				}
+				if err := os.MkdirAll(filepath.Dir(d.path), 0755); err != nil {
+					return err
+				}
+				if err := os.WriteFile(d.path, data, 0644); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("添付ファイルのダウンロードエラー: %w", err)
+		}
+	}
+
+	fixture := fixtureFile{Query: flagFixturesQuery, Records: records}
+	fixture.App.ID = appID
+	if fixture.Records == nil {
+		fixture.Records = []kintone.Record{}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(fixture); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(outPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("フィクスチャの書き込みエラー: %w", err)
+	}
+
+	ui.Success(fmt.Sprintf("%s に %d件のレコードを書き出しました（添付ファイル %d件）", relOut, len(records), len(downloads)))
+	return nil
+}
+
+func runFixturesImport(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		return fmt.Errorf("設定ファイルが見つかりません。先に kpdev init を実行してください: %w", err)
+	}
+
+	appID, err := fixtureAppID(cfg)
+	if err != nil {
+		return err
+	}
+
+	fixturePath := args[0]
+	if !filepath.IsAbs(fixturePath) {
+		fixturePath = filepath.Join(cwd, fixturePath)
+	}
+	records, err := kintone.LoadRecordsFile(fixturePath)
+	if err != nil {
+		return fmt.Errorf("フィクスチャの読み込みエラー: %w", err)
+	}
+
+	// 添付ファイルの存在を API を呼ぶ前に確認する
+	uploads, err := fixtureAttachments(fixturePath, records)
+	if err != nil {
+		return err
+	}
+
+	client, err := newEnvClient(cwd, cfg, flagFixturesEnv)
+	if err != nil {
+		return err
+	}
+
+	// フィクスチャのフィールドがアプリに存在するか確認する
+	var fields map[string]kintone.FieldProperty
+	err = ui.SpinnerWithResult(fmt.Sprintf("アプリ %s のフィールド設定を取得中...", appID), func() error {
+		var fetchErr error
+		fields, fetchErr = client.GetFormFields(appID)
+		return fetchErr
+	})
+	if err != nil {
+		return fmt.Errorf("フィールド設定の取得エラー: %w", err)
+	}
+	if unknown := unknownFixtureFields(records, fields); len(unknown) > 0 {
+		return fmt.Errorf("アプリ %s に存在しないフィールドがあります: %s", appID, strings.Join(unknown, ", "))
+	}
+
+	if flagFixturesDryRun {
+		fmt.Println()
+		ui.Info("ドライラン: アプリへの書き込みは行いません")
+		if flagFixturesReplace {
+			existing, err := client.GetRecordsByCursor(appID, "", []string{"$id"})
+			if err != nil {
+				return fmt.Errorf("レコード取得エラー: %w", err)
+			}
+			fmt.Printf("  削除するレコード: %d件\n", len(existing))
+		}
+		fmt.Printf("  登録するレコード: %d件\n", len(records))
+		fmt.Printf("  添付ファイル:     %d件\n", len(uploads))
+		return nil
+	}
+
+	// 本番環境のアプリへの書き込みは確認する（--replace では既存のレコードがすべて消えるため）
+	if flagFixturesEnv != "" && flagFixturesEnv != "dev" && !flagFixturesForce {
+		message := fmt.Sprintf("本番環境「%s」のアプリ %s に %d件のレコードを登録しますか?", flagFixturesEnv, appID, len(records))
+		if flagFixturesReplace {
+			message = fmt.Sprintf("本番環境「%s」のアプリ %s のレコードをすべて削除し、%d件のレコードを登録しますか?", flagFixturesEnv, appID, len(records))
+		}
+		confirm, err := prompt.AskConfirm(message, false)
+		if err != nil {
+			if errors.Is(err, huh.ErrUserAborted) {
+				return nil
+			}
+			return err
+		}
+		if !confirm {
+			fmt.Println("キャンセルしました")
+			return nil
+		}
+	}
+
+	// 削除の前にアップロードを済ませる（アップロードに失敗しても既存のレコードは残る）
+	if err := uploadFixtureAttachments(client, fixturePath, records, len(uploads)); err != nil {
+		return err
+	}
+
+	writable := make([]kintone.Record, 0, len(records))
+	for _, r := range records {
+		writable = append(writable, kintone.WritableRecord(r))
+	}
+
+	if flagFixturesReplace {
+		var deleted int
+		err = ui.SpinnerWithResult("既存のレコードを削除中...", func() error {
+			var deleteErr error
+			deleted, deleteErr = client.DeleteAllRecords(appID)
+			return deleteErr
+		})
+		if err != nil {
+			return fmt.Errorf("レコード削除エラー: %w", err)
+		}
+		ui.Info(fmt.Sprintf("既存のレコードを削除しました（%d件）", deleted))
+	}
+
+	err = ui.SpinnerWithResult(fmt.Sprintf("レコードを登録中（%d件）...", len(writable)), func() error {
+		return client.AddRecords(appID, writable)
+	})
+	if err != nil {
+		if flagFixturesReplace {
+			return fmt.Errorf("レコード登録エラー（既存のレコードは削除済みです。kpdev fixtures import で登録し直してください）: %w", err)
+		}
+		return fmt.Errorf("レコード登録エラー: %w", err)
+	}
+
+	ui.Success(fmt.Sprintf("アプリ %s に %d件のレコードを登録しました（添付ファイル %d件）", appID, len(writable), len(uploads)))
+	return nil
+}
+
+// fixtureRecordID はレコードの $id を返す（添付ファイルの保存先に使う）
+func fixtureRecordID(record kintone.Record) string {
+	if field, ok := record["$id"].(map[string]interface{}); ok {
+		if id := fmt.Sprintf("%v", field["value"]); id != "" {
+			return id
+		}
+	}
+	return "_"
+}
+
+// unknownFixtureFields はアプリに存在しない（登録対象の）フィールドコードを返す
+func unknownFixtureFields(records []kintone.Record, fields map[string]kintone.FieldProperty) []string {
+	seen := map[string]bool{}
+	var unknown []string
+	for _, r := range records {
+		for code := range kintone.WritableRecord(r) {
+			if _, ok := fields[code]; !ok && !seen[code] {
+				seen[code] = true
+				unknown = append(unknown, code)
+			}
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// fixtureAttachments はフィクスチャの添付ファイルのパスを返す（path はフィクスチャファイルからの相対パス）
+// API を呼ぶ前に、すべてのファイルが存在することを確認する
+func fixtureAttachments(fixturePath string, records []kintone.Record) ([]string, error) {
+	var paths []string
+	for i, record := range records {
+		err := kintone.MapFileFields(record, func(code string, files []interface{}) ([]interface{}, error) {
+			for _, f := range files {
+				file, _ := f.(map[string]interface{})
+				rel, _ := file["path"].(string)
+				if rel == "" {
+					return nil, fmt.Errorf("%d件目のフィールド %s: 添付ファイルに path がありません（kpdev fixtures export で書き出してください）", i+1, code)
+				}
+				path := filepath.Join(filepath.Dir(fixturePath), filepath.FromSlash(rel))
+				if !fileExists(path) {
+					return nil, fmt.Errorf("%d件目のフィールド %s: 添付ファイル %s が見つかりません", i+1, code, rel)
+				}
+				paths = append(paths, path)
+			}
+			return files, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+// uploadFixtureAttachments はフィクスチャの添付ファイルをアップロードし、FILE フィールドの値を fileKey に置き換える
+func uploadFixtureAttachments(client *kintone.Client, fixturePath string, records []kintone.Record, count int) error {
+	if count == 0 {
+		return nil
+	}
+	err := ui.SpinnerWithResult(fmt.Sprintf("添付ファイルをアップロード中（%d件）...", count), func() error {
+		for _, record := range records {
+			err := kintone.MapFileFields(record, func(code string, files []interface{}) ([]interface{}, error) {
+				mapped := make([]interface{}, 0, len(files))
+				for _, f := range files {
+					file, _ := f.(map[string]interface{})
+					rel, _ := file["path"].(string)
+					fileKey, err := client.UploadFile(filepath.Join(filepath.Dir(fixturePath), filepath.FromSlash(rel)))
+					if err != nil {
+						return nil, fmt.Errorf("%s: %w", rel, err)
+					}
+					mapped = append(mapped, map[string]interface{}{"fileKey": fileKey})
+				}
+				return mapped, nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("添付ファイルのアップロードエラー: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,442 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kintone/kpdev/internal/config"
+	"github.com/kintone/kpdev/internal/kintone"
+	"github.com/kintone/kpdev/internal/prompt"
+	"github.com/kintone/kpdev/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// customizeDir は取り込んだカスタマイズを置くディレクトリ（src/main/ からの相対パス）
+const customizeDir = "customize"
+
+// customizeReportFile は取り込み結果のレポート
+const customizeReportFile = "import-report.md"
+
+var (
+	flagImportApp   string
+	flagImportEnv   string
+	flagImportForce bool
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "既存のカスタマイズをプロジェクトに取り込む",
+}
+
+var importCustomizeCmd = &cobra.Command{
+	Use:   "customize",
+	Short: "アプリの JavaScript / CSS カスタマイズを取り込む",
+	Long: `アプリの JavaScript / CSS カスタマイズ（customize.json）をプラグインプロジェクトに取り込みます。
+
+アップロードされたファイルは src/main/customize/ にダウンロードし、kintone での読み込み順に
+import するエントリーを生成して src/main のエントリーから読み込みます。
+CDN などのURLは .kpdev/manifest.json に外部リソースとして追加します。
+変換できなかった内容は src/main/customize/import-report.md に記録します。`,
+	Example: `  kpdev import customize --app 123
+  kpdev import customize --app 123 --env production`,
+	Args: cobra.NoArgs,
+	RunE: runImportCustomize,
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.AddCommand(importCustomizeCmd)
+
+	importCustomizeCmd.Flags().StringVar(&flagImportApp, "app", "", "アプリID")
+	importCustomizeCmd.Flags().StringVar(&flagImportEnv, "env", "dev", "取得元の環境（dev または本番環境の name）")
+	importCustomizeCmd.Flags().BoolVarP(&flagImportForce, "force", "f", false, "取り込み済みの src/main/customize/ を上書きする")
+	importCustomizeCmd.MarkFlagRequired("app")
+}
+
+// customizeImport はカスタマイズの取り込み結果
+type customizeImport struct {
+	imports   map[string][]string // target → エントリーから import するパス（customize/ からの相対）
+	externals map[string][]string // "desktop.js" など → バンドルより前に読み込む外部URL
+	after     map[string][]string // "desktop.js" など → バンドルより後に読み込む外部URL
+	warnings  []string
+}
+
+func runImportCustomize(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		return fmt.Errorf("設定ファイルが見つかりません。先に kpdev init を実行してください: %w", err)
+	}
+
+	mainEntry := filepath.Join(cwd, filepath.FromSlash(strings.TrimPrefix(cfg.Dev.Entry.Main, "/")))
+	if !fileExists(mainEntry) {
+		return fmt.Errorf("エントリー %s が見つかりません", cfg.Dev.Entry.Main)
+	}
+
+	outDir := filepath.Join(filepath.Dir(mainEntry), customizeDir)
+	if fileExists(outDir) && !flagImportForce {
+		rel, _ := filepath.Rel(cwd, outDir)
+		return fmt.Errorf("%s は既に存在します。上書きする場合は --force を指定してください", rel)
+	}
+
+	client, err := newEnvClient(cwd, cfg, flagImportEnv)
+	if err != nil {
+		return err
+	}
+
+	var customize *kintone.AppCustomize
+	err = ui.SpinnerWithResult(fmt.Sprintf("アプリ %s のカスタマイズ設定を取得中...", flagImportApp), func() error {
+		var fetchErr error
+		customize, fetchErr = client.GetAppCustomize(flagImportApp)
+		return fetchErr
+	})
+	if err != nil {
+		return fmt.Errorf("カスタマイズ設定の取得エラー: %w", err)
+	}
+
+	total := len(customize.Desktop.JS) + len(customize.Desktop.CSS) + len(customize.Mobile.JS) + len(customize.Mobile.CSS)
+	if total == 0 {
+		ui.Info(fmt.Sprintf("アプリ %s には JavaScript / CSS カスタマイズが設定されていません", flagImportApp))
+		return nil
+	}
+
+	if err := os.RemoveAll(outDir); err != nil {
+		return err
+	}
+
+	result := &customizeImport{
+		imports:   map[string][]string{},
+		externals: map[string][]string{},
+		after:     map[string][]string{},
+	}
+	if customize.Scope != "" && customize.Scope != "ALL" {
+		result.warnings = append(result.warnings, fmt.Sprintf("適用範囲（scope）が %s でした。プラグインはアプリのすべてのユーザーに適用されます", customize.Scope))
+	}
+
+	err = ui.SpinnerWithResult(fmt.Sprintf("ファイルをダウンロード中（%d件）...", total), func() error {
+		written := map[[32]byte]string{} // 内容 → 保存したパス（desktop と mobile で同じファイルは1つにまとめる）
+		for _, t := range []struct {
+			name   string
+			target kintone.CustomizeTarget
+		}{
+			{"desktop", customize.Desktop},
+			{"mobile", customize.Mobile},
+		} {
+			if err := importCustomizeResources(client, outDir, t.name, "js", t.target.JS, written, result); err != nil {
+				return err
+			}
+			if err := importCustomizeResources(client, outDir, t.name, "css", t.target.CSS, written, result); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("ファイルのダウンロードエラー: %w", err)
+	}
+
+	if (len(customize.Mobile.JS) > 0 || len(customize.Mobile.CSS) > 0) && !cfg.Targets.Mobile {
+		result.warnings = append(result.warnings, "モバイル用のカスタマイズがありますが、プラグインの対象にモバイルが含まれていません。kpdev config で有効にしてください")
+	}
+
+	// エントリーを生成し、src/main のエントリーから読み込む
+	entryExt := ".js"
+	if detectCurrentLanguage(cwd) == prompt.LanguageTypeScript {
+		entryExt = ".ts"
+	}
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(outDir, "index"+entryExt), []byte(customizeEntry(flagImportApp, result)), 0644); err != nil {
+		return fmt.Errorf("エントリーの書き込みエラー: %w", err)
+	}
+	hooked, err := addCustomizeImport(mainEntry)
+	if err != nil {
+		return fmt.Errorf("%s の更新エラー: %w", cfg.Dev.Entry.Main, err)
+	}
+
+	// 外部URLを manifest.json に追加
+	if len(result.externals) > 0 || len(result.after) > 0 {
+		manifest, err := loadBuildManifest(cwd)
+		if err != nil {
+			return fmt.Errorf("manifest.json の読み込みに失敗しました: %w", err)
+		}
+		for _, target := range []string{"desktop", "mobile"} {
+			for _, kind := range []string{"js", "css"} {
+				key := target + "." + kind
+				addManifestExternals(manifest, target, kind, result.externals[key], result.after[key])
+			}
+		}
+		if err := saveBuildManifest(cwd, manifest); err != nil {
+			return fmt.Errorf("manifest.json の保存に失敗しました: %w", err)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(outDir, customizeReportFile), []byte(customizeReport(flagImportApp, flagImportEnv, result)), 0644); err != nil {
+		return fmt.Errorf("レポートの書き込みエラー: %w", err)
+	}
+
+	relOut, _ := filepath.Rel(cwd, outDir)
+	fmt.Println()
+	ui.Success(fmt.Sprintf("アプリ %s のカスタマイズを %s に取り込みました", flagImportApp, relOut))
+	if hooked {
+		fmt.Printf("  %s から %s を読み込むようにしました\n", cfg.Dev.Entry.Main, "./"+customizeDir)
+	}
+	externals := 0
+	for _, urls := range result.externals {
+		externals += len(urls)
+	}
+	for _, urls := range result.after {
+		externals += len(urls)
+	}
+	if externals > 0 {
+		fmt.Printf("  外部URL %d件を .kpdev/manifest.json に追加しました\n", externals)
+	}
+
+	if len(result.warnings) > 0 {
+		fmt.Println()
+		for _, w := range result.warnings {
+			ui.Warn(w)
+		}
+	}
+	fmt.Println()
+	fmt.Printf("取り込み結果: %s\n", ui.InfoStyle.Render(filepath.Join(relOut, customizeReportFile)))
+	return nil
+}
+
+// importCustomizeResources は js または css のリソースをダウンロード・外部URLとして振り分ける
+func importCustomizeResources(client *kintone.Client, outDir, target, kind string, resources []kintone.CustomizeResource, written map[[32]byte]string, result *customizeImport) error {
+	seenFile := false
+	for _, r := range resources {
+		switch {
+		case r.Type == "URL":
+			if !strings.HasPrefix(r.URL, "https://") {
+				result.warnings = append(result.warnings, fmt.Sprintf("%s の %s %s は https ではないため、プラグインでは読み込めません", targetLabel(target), kind, r.URL))
+				continue
+			}
+			// ファイルより後に読み込まれていたURLはバンドルより後に読み込む
+			key := target + "." + kind
+			if seenFile {
+				result.after[key] = append(result.after[key], r.URL)
+			} else {
+				result.externals[key] = append(result.externals[key], r.URL)
+			}
+
+		case r.Type == "FILE" && r.File != nil:
+			seenFile = true
+			data, err := client.DownloadFile(r.File.FileKey)
+			if err != nil {
+				return fmt.Errorf("%s: %w", r.File.Name, err)
+			}
+
+			sum := sha256.Sum256(data)
+			if rel, ok := written[sum]; ok {
+				result.imports[target] = append(result.imports[target], rel)
+				continue
+			}
+
+			rel := uniqueCustomizePath(outDir, path.Join(target, sanitizeFileName(r.File.Name)))
+			dst := filepath.Join(outDir, filepath.FromSlash(rel))
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(dst, data, 0644); err != nil {
+				return err
+			}
+			written[sum] = rel
+			result.imports[target] = append(result.imports[target], rel)
+
+		default:
+			result.warnings = append(result.warnings, fmt.Sprintf("%s の %s に未対応の種類（%s）のリソースがあります", targetLabel(target), kind, r.Type))
+		}
+	}
+	return nil
+}
+
+// uniqueCustomizePath は同名のファイルがあれば -2, -3 ... を付けたパスを返す
+func uniqueCustomizePath(outDir, rel string) string {
+	ext := path.Ext(rel)
+	base := strings.TrimSuffix(rel, ext)
+	candidate := rel
+	for i := 2; fileExists(filepath.Join(outDir, filepath.FromSlash(candidate))); i++ {
+		candidate = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+	return candidate
+}
+
+// sanitizeFileName はファイル名からパス区切りを取り除く
+func sanitizeFileName(name string) string {
+	name = strings.NewReplacer("/", "_", "\\", "_").Replace(name)
+	if name == "" || name == "." || name == ".." {
+		return "file"
+	}
+	return name
+}
+
+func targetLabel(target string) string {
+	if target == "mobile" {
+		return "モバイル"
+	}
+	return "PC"
+}
+
+// customizeEntry は取り込んだファイルを kintone での読み込み順に import するエントリーを生成する
+func customizeEntry(appID string, result *customizeImport) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "// kpdev import customize でアプリ %s から取り込んだファイル\n", appID)
+	sb.WriteString("// kintone での読み込み順（JavaScript → CSS）に並んでいます\n")
+
+	imported := map[string]bool{}
+	for _, target := range []string{"desktop", "mobile"} {
+		var lines []string
+		for _, rel := range result.imports[target] {
+			if imported[rel] {
+				continue
+			}
+			imported[rel] = true
+			lines = append(lines, fmt.Sprintf("import './%s'\n", rel))
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n// %s\n", targetLabel(target))
+		for _, line := range lines {
+			sb.WriteString(line)
+		}
+	}
+
+	if len(imported) == 0 {
+		sb.WriteString("\nexport {}\n")
+	}
+	return sb.String()
+}
+
+// addCustomizeImport は src/main のエントリーの先頭に import './customize' を追加する
+// 既に追加済みの場合は false を返す
+func addCustomizeImport(mainEntry string) (bool, error) {
+	data, err := os.ReadFile(mainEntry)
+	if err != nil {
+		return false, err
+	}
+
+	line := "import './" + customizeDir + "'"
+	for _, l := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(l) == line {
+			return false, nil
+		}
+	}
+
+	return true, os.WriteFile(mainEntry, []byte(line+"\n"+string(data)), 0644)
+}
+
+// addManifestExternals は manifest.json の desktop / mobile に外部URLを追加する
+// before はバンドルのファイルの直前、after はバンドルより後の既存のリソースの後ろに並べる
+func addManifestExternals(manifest map[string]interface{}, target, kind string, before, after []string) {
+	if len(before) == 0 && len(after) == 0 {
+		return
+	}
+
+	t, ok := manifest[target].(map[string]interface{})
+	if !ok {
+		t = map[string]interface{}{}
+		manifest[target] = t
+	}
+	entries, _ := t[kind].([]interface{})
+
+	// バンドルの記述がなければ末尾に追加し、前後の位置の目印にする
+	bundle := config.BundleResourcePath(target, kind)
+	bundleAt := -1
+	exists := map[string]bool{}
+	for i, e := range entries {
+		s, _ := e.(string)
+		exists[s] = true
+		if s == bundle {
+			bundleAt = i
+		}
+	}
+	if bundleAt < 0 {
+		bundleAt = len(entries)
+		entries = append(entries, bundle)
+	}
+
+	added := func(urls []string) []interface{} {
+		var list []interface{}
+		for _, u := range urls {
+			if !exists[u] {
+				exists[u] = true
+				list = append(list, u)
+			}
+		}
+		return list
+	}
+	addedBefore := added(before)
+	addedAfter := added(after)
+
+	merged := make([]interface{}, 0, len(entries)+len(addedBefore)+len(addedAfter))
+	merged = append(merged, entries[:bundleAt]...)
+	merged = append(merged, addedBefore...)
+	merged = append(merged, entries[bundleAt:]...)
+	merged = append(merged, addedAfter...)
+	t[kind] = merged
+}
+
+// customizeReport は取り込み結果のレポート（Markdown）を生成する
+func customizeReport(appID, env string, result *customizeImport) string {
+	var sb strings.Builder
+	sb.WriteString("# JavaScript / CSS カスタマイズの取り込み結果\n\n")
+	fmt.Fprintf(&sb, "- アプリ: %s（%s）\n", appID, env)
+	fmt.Fprintf(&sb, "- 取り込み日時: %s\n", time.Now().Format("2006-01-02 15:04:05"))
+
+	sb.WriteString("\n## 取り込んだファイル\n\n")
+	count := 0
+	for _, target := range []string{"desktop", "mobile"} {
+		for _, rel := range result.imports[target] {
+			fmt.Fprintf(&sb, "- %s: `%s`\n", targetLabel(target), rel)
+			count++
+		}
+	}
+	if count == 0 {
+		sb.WriteString("なし\n")
+	}
+
+	sb.WriteString("\n## 外部URL（.kpdev/manifest.json に追加）\n\n")
+	count = 0
+	for _, target := range []string{"desktop", "mobile"} {
+		for _, kind := range []string{"js", "css"} {
+			for _, u := range result.externals[target+"."+kind] {
+				fmt.Fprintf(&sb, "- %s %s: %s\n", targetLabel(target), kind, u)
+				count++
+			}
+			for _, u := range result.after[target+"."+kind] {
+				fmt.Fprintf(&sb, "- %s %s: %s（バンドルの後）\n", targetLabel(target), kind, u)
+				count++
+			}
+		}
+	}
+	if count == 0 {
+		sb.WriteString("なし\n")
+	}
+
+	sb.WriteString("\n## 変換できなかった内容\n\n")
+	if len(result.warnings) == 0 {
+		sb.WriteString("なし\n")
+	}
+	for _, w := range result.warnings {
+		fmt.Fprintf(&sb, "- %s\n", w)
+	}
+
+	sb.WriteString("\n## 確認事項\n\n")
+	sb.WriteString("- 取り込んだファイルは ES モジュールとしてバンドルされます。ファイル間でグローバル変数・関数を共有している場合は `window` のプロパティとして参照してください\n")
+	sb.WriteString("- PC用とモバイル用のファイルは同じバンドルに含まれます\n")
+	sb.WriteString("- 動作を確認したら、アプリの JavaScript / CSS カスタマイズから元のファイルを削除してください（二重に実行されます）\n")
+	return sb.String()
+}
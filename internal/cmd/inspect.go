@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/kintone/kpdev/internal/config"
+	"github.com/kintone/kpdev/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect",
+	Short: "ビルドプロファイル・バリアント・本番環境の設定を表示",
+	Long: `プロジェクトのビルドプロファイル、バリアントのプラグインID、本番環境ごとの定数、
+前回デプロイした ZIP とその定数を表示します。
+
+どの環境にどのビルドがデプロイされているかの確認に使います。`,
+	RunE: runInspect,
+}
+
+func init() {
+	rootCmd.AddCommand(inspectCmd)
+}
+
+func runInspect(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		return fmt.Errorf("設定ファイルが見つかりません。先に kpdev init を実行してください: %w", err)
+	}
+
+	if manifest, err := loadBuildManifest(cwd); err == nil {
+		name := ""
+		if n, ok := manifest["name"].(map[string]interface{}); ok {
+			name = fmt.Sprintf("%v", n["ja"])
+		}
+		fmt.Printf("プラグイン: %s %s\n\n", ui.InfoStyle.Render(name), ui.MutedStyle.Render(fmt.Sprintf("v%v", manifest["version"])))
+	}
+
+	// ビルドプロファイル
+	fmt.Println("ビルドプロファイル:")
+	for _, name := range cfg.ProfileNames() {
+		profile, err := cfg.Profile(name)
+		if err != nil {
+			fmt.Printf("  %s%s\n", padCell(name, 12), ui.ErrorStyle.Render(err.Error()))
+			continue
+		}
+		fmt.Printf("  %s%s\n", padCell(name, 12), profile.Summary())
+		if len(profile.Define) > 0 {
+			fmt.Printf("  %s%s\n", padCell("", 12), ui.MutedStyle.Render("define: "+formatEnvConstants(profile.Define)))
+		}
+	}
+	fmt.Println()
+
+	// バリアント
+	variants, err := config.LoadVariants(cwd)
+	if err != nil {
+		return fmt.Errorf("%s の読み込みに失敗しました: %w", config.VariantsFile, err)
+	}
+	if len(variants.Variants) > 0 {
+		fmt.Println("バリアント:")
+		for _, name := range variants.Names() {
+			variant := variants.Variants[name]
+			pluginID := variant.PluginID
+			if pluginID == "" {
+				pluginID = ui.MutedStyle.Render("未ビルド")
+			}
+			variantName := ""
+			if n, ok := variant.Manifest["name"].(map[string]interface{}); ok {
+				for _, lang := range []string{"ja", "en"} {
+					if s, ok := n[lang].(string); ok {
+						variantName = s
+						break
+					}
+				}
+			}
+			fmt.Printf("  %s%s  %s\n", padCell(name, 12), pluginID, variantName)
+		}
+		fmt.Println()
+	}
+
+	// 本番環境
+	if len(cfg.Kintone.Prod) == 0 {
+		fmt.Printf("本番環境: %s\n", ui.MutedStyle.Render("未設定（kpdev deploy で追加できます）"))
+		return nil
+	}
+
+	deployState, err := config.LoadDeployState(cwd)
+	if err != nil {
+		return fmt.Errorf("%s の読み込みに失敗しました: %w", config.DeployStateFile, err)
+	}
+
+	fmt.Println("本番環境:")
+	for _, prod := range cfg.Kintone.Prod {
+		fmt.Printf("  %s %s\n", ui.InfoStyle.Render(prod.Name), ui.MutedStyle.Render(prod.URL()))
+		if len(prod.Env) > 0 {
+			fmt.Printf("    定数:             %s\n", formatEnvConstants(prod.Env))
+		}
+
+		deployed := deployState.Environments[prod.Name]
+		if deployed == nil {
+			fmt.Printf("    前回のデプロイ:   %s\n", ui.MutedStyle.Render("記録なし"))
+			continue
+		}
+		line := "v" + deployed.Version
+		if deployed.Artifact != "" {
+			line += "  " + deployed.Artifact
+		}
+		fmt.Printf("    前回のデプロイ:   %s  %s\n", line, ui.MutedStyle.Render(deployed.DeployedAt))
+		if len(deployed.Env) > 0 {
+			fmt.Printf("    デプロイ時の定数: %s\n", formatEnvConstants(deployed.Env))
+		}
+		if !sameEnvConstants(prod.Env, deployed.Env) {
+			fmt.Printf("    %s\n", ui.WarnStyle.Render("定数が前回のデプロイから変わっています（kpdev deploy で反映されます）"))
+		}
+	}
+	return nil
+}
+
+// sameEnvConstants は2つの定数の組み合わせが同じかどうかを返す
+func sameEnvConstants(a, b map[string]interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, key := range slices.Sorted(maps.Keys(a)) {
+		x, _ := json.Marshal(a[key])
+		y, ok := b[key]
+		if !ok {
+			return false
+		}
+		if z, _ := json.Marshal(y); string(x) != string(z) {
+			return false
+		}
+	}
+	return true
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/huh"
@@ -67,20 +68,37 @@ func runMigrate(cmd *cobra.Command, args []string) error {
 		updates = append(updates, "eslint.config.js を生成")
 	}
 
-	// 3. vite.config.ts の更新 (Vite 7対応)
+	// 3. vite.config.ts の更新 (Vite 7対応・スキーマ更新)
 	viteConfigPath := filepath.Join(config.GetConfigDir(cwd), "vite.config.ts")
 	if _, err := os.Stat(viteConfigPath); err == nil {
 		// 既存の vite.config.ts を確認
 		data, err := os.ReadFile(viteConfigPath)
 		if err == nil {
 			content := string(data)
-			if containsHelper(content, "handleHotUpdate") {
-				updates = append(updates, "vite.config.ts を Vite 7 対応版に更新")
+			if generator.IsViteConfigOutdated(content) {
+				if containsHelper(content, "handleHotUpdate") {
+					updates = append(updates, "vite.config.ts を Vite 7 対応版に更新")
+				} else {
+					updates = append(updates, fmt.Sprintf("vite.config.ts を最新版に更新 (schema v%d)", generator.ViteConfigSchemaVersion))
+				}
 			}
 		}
 	}
 
-	// 4. manifest.json のプロパティ順序
+	// 4. 開発用ローダーの更新
+	loaderOutdated := false
+	if meta, err := generator.LoadLoaderMeta(cwd); err == nil && meta.SchemaVersion < generator.LoaderSchemaVersion {
+		loaderOutdated = true
+		updates = append(updates, fmt.Sprintf("開発用ローダーを更新 (v%d → v%d)", meta.SchemaVersion, generator.LoaderSchemaVersion))
+	}
+
+	// 5. .gitignore の kpdev 管理エントリ
+	missingIgnores := generator.MissingGitignoreEntries(cwd)
+	if len(missingIgnores) > 0 {
+		updates = append(updates, fmt.Sprintf(".gitignore に %s を追加", strings.Join(missingIgnores, ", ")))
+	}
+
+	// 6. manifest.json のプロパティ順序
 	manifestPath := filepath.Join(config.GetConfigDir(cwd), "manifest.json")
 	if _, err := os.Stat(manifestPath); err == nil {
 		updates = append(updates, "manifest.json のプロパティ順序を標準化")
@@ -166,7 +184,7 @@ func runMigrate(cmd *cobra.Command, args []string) error {
 	// 3. vite.config.ts の更新
 	if _, err := os.Stat(viteConfigPath); err == nil {
 		data, _ := os.ReadFile(viteConfigPath)
-		if containsHelper(string(data), "handleHotUpdate") {
+		if generator.IsViteConfigOutdated(string(data)) {
 			fmt.Printf("  vite.config.ts を更新中...")
 			if err := generator.GenerateViteConfig(cwd, framework, language); err != nil {
 				fmt.Printf(" %s\n", ui.WarnStyle.Render(ui.IconError))
@@ -176,7 +194,27 @@ func runMigrate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// 4. manifest.json のプロパティ順序を標準化
+	// 4. 開発用ローダーの更新
+	if loaderOutdated {
+		fmt.Printf("  開発用ローダーを更新中...")
+		if err := generator.RegenerateLoaderScripts(cwd); err != nil {
+			fmt.Printf(" %s\n", ui.WarnStyle.Render(ui.IconError))
+			return fmt.Errorf("ローダー更新エラー: %w", err)
+		}
+		fmt.Printf(" %s\n", ui.SuccessStyle.Render(ui.IconSuccess))
+	}
+
+	// 5. .gitignore の更新
+	if len(missingIgnores) > 0 {
+		fmt.Printf("  .gitignore を更新中...")
+		if err := generator.AppendGitignoreEntries(cwd, missingIgnores); err != nil {
+			fmt.Printf(" %s\n", ui.WarnStyle.Render(ui.IconError))
+			return fmt.Errorf(".gitignore 更新エラー: %w", err)
+		}
+		fmt.Printf(" %s\n", ui.SuccessStyle.Render(ui.IconSuccess))
+	}
+
+	// 6. manifest.json のプロパティ順序を標準化
 	if _, err := os.Stat(manifestPath); err == nil {
 		fmt.Printf("  manifest.json を標準化中...")
 		if err := standardizeManifest(manifestPath); err != nil {
@@ -188,6 +226,9 @@ func runMigrate(cmd *cobra.Command, args []string) error {
 
 	fmt.Println()
 	ui.Success("プロジェクトを更新しました")
+	if loaderOutdated {
+		fmt.Println("  次回の kpdev dev で開発用ローダーが再デプロイされます")
+	}
 	fmt.Println()
 	ui.Info("バックアップは以下にあります:")
 	fmt.Printf("  %s\n", backupDir)
@@ -205,8 +246,10 @@ func createBackup(projectDir, backupDir string) error {
 		".kpdev/config.json",
 		".kpdev/manifest.json",
 		".kpdev/vite.config.ts",
+		".kpdev/managed/loader.meta.json",
 		"eslint.config.js",
 		"package.json",
+		".gitignore",
 	}
 
 	for _, file := range backupFiles {
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kintone/kpdev/internal/mockserver"
+	"github.com/kintone/kpdev/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagMockHost     string
+	flagMockPort     int
+	flagMockUsername string
+	flagMockPassword string
+	flagMockFail     []string
+)
+
+var mockServerCmd = &cobra.Command{
+	Use:   "mock-server",
+	Short: "kintone のモックサーバーを起動",
+	Long: `プラグインのアップロード・インポート・一覧取得 API を模したモックサーバーを起動します。
+
+.kpdev/config.json の環境に "baseUrl": "http://localhost:3100" を指定すると、
+kpdev dev / kpdev deploy の接続先をモックサーバーに切り替えられます。`,
+	Example: `  kpdev mock-server
+  kpdev mock-server --username admin --password secret
+  kpdev mock-server --fail import.json=500 --fail file.json=503x2`,
+	RunE: runMockServer,
+}
+
+func init() {
+	rootCmd.AddCommand(mockServerCmd)
+
+	mockServerCmd.Flags().StringVar(&flagMockHost, "host", "127.0.0.1", "待ち受けるホスト")
+	mockServerCmd.Flags().IntVarP(&flagMockPort, "port", "p", 3100, "待ち受けるポート")
+	mockServerCmd.Flags().StringVar(&flagMockUsername, "username", "", "認証を検証するユーザー名（省略時は検証しない）")
+	mockServerCmd.Flags().StringVar(&flagMockPassword, "password", "", "認証を検証するパスワード")
+	mockServerCmd.Flags().StringArrayVar(&flagMockFail, "fail", nil, "API を失敗させる（例: import.json=500, file.json=503x2）")
+}
+
+func runMockServer(cmd *cobra.Command, args []string) error {
+	server := mockserver.New(mockserver.Options{
+		Username: flagMockUsername,
+		Password: flagMockPassword,
+		Logf: func(format string, args ...interface{}) {
+			fmt.Printf("%s %s\n", ui.MutedStyle.Render(time.Now().Format("15:04:05")), fmt.Sprintf(format, args...))
+		},
+	})
+
+	for _, spec := range flagMockFail {
+		f, err := mockserver.ParseFailure(spec)
+		if err != nil {
+			return err
+		}
+		server.InjectFailure(f)
+	}
+
+	addr := net.JoinHostPort(flagMockHost, strconv.Itoa(flagMockPort))
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("モックサーバー起動エラー: %w", err)
+	}
+
+	baseURL := "http://" + listener.Addr().String()
+
+	fmt.Printf("モックサーバー:\n")
+	fmt.Printf("  %s\n", ui.InfoStyle.Render(baseURL))
+	fmt.Println()
+
+	fmt.Printf("接続するには .kpdev/config.json の環境に追加:\n")
+	fmt.Printf("  %s\n", ui.MutedStyle.Render(`"baseUrl": "`+baseURL+`"`))
+	fmt.Println()
+
+	if flagMockUsername != "" || flagMockPassword != "" {
+		fmt.Printf("認証: %s\n", flagMockUsername)
+	} else {
+		fmt.Printf("認証: %s\n", ui.MutedStyle.Render("検証しない"))
+	}
+	for _, spec := range flagMockFail {
+		fmt.Printf("失敗の注入: %s\n", ui.WarnStyle.Render(spec))
+	}
+	fmt.Println()
+
+	httpServer := &http.Server{Handler: server}
+
+	// Ctrl+C で停止
+	sigChan := ui.SetupSignalHandler()
+	go func() {
+		<-sigChan
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(ctx)
+	}()
+
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("モックサーバーエラー: %w", err)
+	}
+
+	fmt.Println()
+	ui.Info("モックサーバーを停止しました")
+	return nil
+}
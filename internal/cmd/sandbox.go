@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/kintone/kpdev/internal/config"
+	"github.com/kintone/kpdev/internal/generator"
+	"github.com/kintone/kpdev/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagSandboxNoBrowser bool
+	flagSandboxMobile    bool
+)
+
+var sandboxCmd = &cobra.Command{
+	Use:   "sandbox",
+	Short: "疑似 kintone 画面でプラグインを動かす",
+	Long: `kintone に接続せずに、疑似的なレコード一覧・詳細画面でプラグインを動かします。
+
+kintone.events.on や kintone.app.record.get/set などはスタブに置き換えられ、
+イベントパネルから .kpdev/sandbox/records.json のレコードでイベントを発火できます。`,
+	RunE: runSandbox,
+}
+
+func init() {
+	rootCmd.AddCommand(sandboxCmd)
+
+	sandboxCmd.Flags().BoolVar(&flagSandboxNoBrowser, "no-browser", false, "ブラウザを自動で開かない")
+	sandboxCmd.Flags().BoolVar(&flagSandboxMobile, "mobile", false, "モバイル画面で開く")
+}
+
+func runSandbox(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		return fmt.Errorf("設定ファイルが見つかりません。先に kpdev init を実行してください: %w", err)
+	}
+
+	meta, err := generator.LoadLoaderMeta(cwd)
+	if err != nil {
+		return fmt.Errorf("loader.meta.json が見つかりません。先に kpdev init を実行してください: %w", err)
+	}
+
+	viteConfigPath := filepath.Join(config.GetConfigDir(cwd), "vite.config.ts")
+	data, err := os.ReadFile(viteConfigPath)
+	if err != nil {
+		return fmt.Errorf("vite.config.ts の読み込みエラー: %w", err)
+	}
+	if generator.IsViteConfigOutdated(string(data)) {
+		return fmt.Errorf("vite.config.ts が古い形式のため kpdev sandbox を使えません。kpdev migrate で更新してください")
+	}
+
+	// フィクスチャを用意
+	created, err := generator.EnsureSandboxFixtures(cwd)
+	if err != nil {
+		return fmt.Errorf("フィクスチャ作成エラー: %w", err)
+	}
+	if created {
+		ui.Success("サンプルのフィクスチャを作成しました: .kpdev/sandbox/records.json")
+		fmt.Println()
+	}
+
+	sandboxURL := meta.Dev.Origin + "/__kpdev/sandbox"
+	if flagSandboxMobile {
+		sandboxURL += "?mobile=1"
+	}
+
+	fmt.Printf("sandbox:\n")
+	fmt.Printf("  %s\n", ui.InfoStyle.Render(sandboxURL))
+	fmt.Println()
+
+	fmt.Printf("フィクスチャ:\n")
+	fmt.Printf("  %s\n", ".kpdev/sandbox/records.json")
+	fmt.Println()
+
+	ui.Info("Dev server を起動中...")
+	fmt.Println()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Ctrl+C で Vite を止める
+	sigChan := ui.SetupSignalHandler()
+	go func() {
+		<-sigChan
+		cancel()
+	}()
+
+	viteCmd := exec.CommandContext(ctx, "npx", "vite", "--config", viteConfigPath)
+	viteCmd.Dir = cwd
+	viteCmd.Stdout = os.Stdout
+	viteCmd.Stderr = os.Stderr
+	viteCmd.Stdin = os.Stdin
+	viteCmd.Env = append(os.Environ(), generator.ViteEntriesEnv(cfg))
+
+	if err := viteCmd.Start(); err != nil {
+		return fmt.Errorf("Vite起動エラー: %w", err)
+	}
+
+	// ブラウザを開く
+	if !flagSandboxNoBrowser {
+		go func() {
+			// Viteが起動するまで少し待つ
+			time.Sleep(1 * time.Second)
+			openBrowser(sandboxURL)
+		}()
+	}
+
+	if err := viteCmd.Wait(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("Vite実行エラー: %w", err)
+	}
+	return nil
+}
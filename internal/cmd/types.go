@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kintone/kpdev/internal/config"
+	"github.com/kintone/kpdev/internal/generator"
+	"github.com/kintone/kpdev/internal/prompt"
+	"github.com/kintone/kpdev/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagTypesApp   string
+	flagTypesEnv   string
+	flagTypesCheck bool
+)
+
+var typesCmd = &cobra.Command{
+	Use:   "types",
+	Short: "kintone アプリの型定義を管理",
+}
+
+var typesPullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "アプリのフォーム設定からレコードの型を生成",
+	Long: `kintone アプリのフィールド設定を取得し、レコードの TypeScript 型を src/types/ に生成します。
+
+--check を指定すると、生成済みの型がアプリの設定と一致しているかだけを確認します（CI向け）。`,
+	Example: `  kpdev types pull --app 123
+  kpdev types pull --app 123 --env production
+  kpdev types pull --app 123 --check`,
+	RunE: runTypesPull,
+}
+
+func init() {
+	rootCmd.AddCommand(typesCmd)
+	typesCmd.AddCommand(typesPullCmd)
+
+	typesPullCmd.Flags().StringVar(&flagTypesApp, "app", "", "アプリID")
+	typesPullCmd.Flags().StringVar(&flagTypesEnv, "env", "dev", "取得元の環境（dev または本番環境の name）")
+	typesPullCmd.Flags().BoolVar(&flagTypesCheck, "check", false, "生成済みの型がアプリと一致しなければエラーにする")
+	typesPullCmd.MarkFlagRequired("app")
+}
+
+func runTypesPull(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		return fmt.Errorf("設定ファイルが見つかりません。先に kpdev init を実行してください: %w", err)
+	}
+
+	if detectCurrentLanguage(cwd) != prompt.LanguageTypeScript {
+		return fmt.Errorf("types pull は TypeScript プロジェクトでのみ使用できます")
+	}
+
+	client, err := newEnvClient(cwd, cfg, flagTypesEnv)
+	if err != nil {
+		return err
+	}
+
+	var content string
+	err = ui.SpinnerWithResult(fmt.Sprintf("アプリ %s のフィールド設定を取得中...", flagTypesApp), func() error {
+		fields, fetchErr := client.GetFormFields(flagTypesApp)
+		if fetchErr != nil {
+			return fetchErr
+		}
+		content = generator.GenerateRecordTypes(flagTypesApp, fields)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("フィールド設定の取得エラー: %w", err)
+	}
+
+	typesPath := generator.RecordTypesPath(cwd, flagTypesApp)
+	relPath, _ := filepath.Rel(cwd, typesPath)
+
+	if flagTypesCheck {
+		current, err := os.ReadFile(typesPath)
+		if err != nil {
+			return fmt.Errorf("%s が見つかりません。kpdev types pull --app %s を実行してください", relPath, flagTypesApp)
+		}
+		if string(current) == content {
+			ui.Success(fmt.Sprintf("%s はアプリ %s と一致しています", relPath, flagTypesApp))
+			return nil
+		}
+
+		fmt.Println()
+		ui.Error(fmt.Sprintf("%s がアプリ %s のフィールド設定と一致しません", relPath, flagTypesApp))
+		printLineDiff(string(current), content)
+		fmt.Println()
+		return fmt.Errorf("型定義が古くなっています。kpdev types pull --app %s で更新してください", flagTypesApp)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(typesPath), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(typesPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("型定義の書き込みエラー: %w", err)
+	}
+
+	ui.Success(fmt.Sprintf("%s を生成しました", relPath))
+	return nil
+}
+
+// printLineDiff は生成済みの型と最新の型で異なる行を表示する
+func printLineDiff(current, latest string) {
+	currentLines := strings.Split(current, "\n")
+	latestLines := strings.Split(latest, "\n")
+
+	inCurrent := make(map[string]bool, len(currentLines))
+	for _, line := range currentLines {
+		inCurrent[line] = true
+	}
+	inLatest := make(map[string]bool, len(latestLines))
+	for _, line := range latestLines {
+		inLatest[line] = true
+	}
+
+	for _, line := range currentLines {
+		if !inLatest[line] {
+			fmt.Printf("  %s\n", ui.ErrorStyle.Render("- "+strings.TrimSpace(line)))
+		}
+	}
+	for _, line := range latestLines {
+		if !inCurrent[line] {
+			fmt.Printf("  %s\n", ui.SuccessStyle.Render("+ "+strings.TrimSpace(line)))
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/kintone/kpdev/internal/config"
+	"github.com/kintone/kpdev/internal/generator"
+	"github.com/kintone/kpdev/internal/kintone"
+	"github.com/kintone/kpdev/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagUsageEnv string
+	flagUsageAll bool
+)
+
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "本番環境でプラグインを使用しているアプリを表示",
+	Long: `本番環境ごとに、本番用プラグイン（loader.meta.json の Prod のプラグインID）を
+追加しているアプリの名前・ID・スペースを表示します。
+
+required_params を追加するバージョンをデプロイする前に、
+プラグインの設定し直しが必要になるアプリを確認するために使います。`,
+	Example: `  kpdev usage --env production
+  kpdev usage --all`,
+	RunE: runUsage,
+}
+
+func init() {
+	rootCmd.AddCommand(usageCmd)
+
+	usageCmd.Flags().StringVar(&flagUsageEnv, "env", "", "対象の本番環境の name")
+	usageCmd.Flags().BoolVar(&flagUsageAll, "all", false, "すべての本番環境を対象にする")
+}
+
+// appUsage はプラグインを使用しているアプリ
+type appUsage struct {
+	ID        string
+	Name      string
+	SpaceName string
+}
+
+func runUsage(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		return fmt.Errorf("設定ファイルが見つかりません。先に kpdev init を実行してください: %w", err)
+	}
+
+	meta, err := generator.LoadLoaderMeta(cwd)
+	if err != nil {
+		return fmt.Errorf("loader.meta.json が見つかりません: %w", err)
+	}
+
+	if len(cfg.Kintone.Prod) == 0 {
+		return fmt.Errorf("本番環境が設定されていません。kpdev config で追加してください")
+	}
+
+	var envs []config.ProdEnvConfig
+	switch {
+	case flagUsageAll:
+		envs = cfg.Kintone.Prod
+	case flagUsageEnv != "":
+		for _, prod := range cfg.Kintone.Prod {
+			if prod.Name == flagUsageEnv {
+				envs = append(envs, prod)
+			}
+		}
+		if len(envs) == 0 {
+			return fmt.Errorf("本番環境が見つかりません: %s", flagUsageEnv)
+		}
+	case len(cfg.Kintone.Prod) == 1:
+		envs = cfg.Kintone.Prod
+	default:
+		return fmt.Errorf("本番環境が複数あります。--env で環境を指定するか、--all を指定してください")
+	}
+
+	cyan := color.New(color.FgCyan).SprintFunc()
+	pluginID := meta.PluginIDs.Prod
+	fmt.Printf("Plugin ID: %s\n", cyan(pluginID))
+
+	failed := 0
+	for _, prod := range envs {
+		fmt.Printf("\n%s %s (%s)\n", cyan("→"), prod.Name, prod.URL())
+
+		client, err := newEnvClient(cwd, cfg, prod.Name)
+		if err != nil {
+			ui.Error(err.Error())
+			failed++
+			continue
+		}
+
+		apps, err := pluginUsage(client, pluginID)
+		if err != nil {
+			ui.Error(fmt.Sprintf("アプリの取得エラー: %v", err))
+			failed++
+			continue
+		}
+		printAppUsage(apps)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d 環境で取得に失敗しました", failed)
+	}
+	return nil
+}
+
+// pluginUsage はプラグインを使用しているアプリをスペース名付きで取得する
+func pluginUsage(client *kintone.Client, pluginID string) ([]appUsage, error) {
+	pluginApps, err := client.GetPluginApps(pluginID)
+	if err != nil {
+		return nil, err
+	}
+	if len(pluginApps) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(pluginApps))
+	for i, a := range pluginApps {
+		ids[i] = a.ID
+	}
+	infos, err := client.GetApps(ids)
+	if err != nil {
+		return nil, err
+	}
+	spaceIDs := map[string]string{}
+	for _, info := range infos {
+		spaceIDs[info.AppID] = info.SpaceID
+	}
+
+	// スペース名は同じスペースを何度も取得しないようにキャッシュする
+	spaceNames := map[string]string{}
+	apps := make([]appUsage, len(pluginApps))
+	for i, a := range pluginApps {
+		apps[i] = appUsage{ID: a.ID, Name: a.Name}
+
+		spaceID := spaceIDs[a.ID]
+		if spaceID == "" {
+			continue
+		}
+		name, ok := spaceNames[spaceID]
+		if !ok {
+			name, err = client.GetSpaceName(spaceID)
+			if err != nil {
+				// 閲覧権限のないスペースは ID のみ表示する
+				name = "スペース " + spaceID
+			}
+			spaceNames[spaceID] = name
+		}
+		apps[i].SpaceName = name
+	}
+	return apps, nil
+}
+
+func printAppUsage(apps []appUsage) {
+	if len(apps) == 0 {
+		fmt.Println(ui.MutedStyle.Render("  プラグインを使用しているアプリはありません"))
+		return
+	}
+
+	fmt.Printf("  %d 個のアプリで使用中\n", len(apps))
+	for _, a := range apps {
+		space := ""
+		if a.SpaceName != "" {
+			space = ui.MutedStyle.Render("（スペース: " + a.SpaceName + "）")
+		}
+		fmt.Printf("  - %s %s %s\n", a.Name, ui.MutedStyle.Render("ID: "+a.ID), space)
+	}
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// BudgetTargets はサイズの上限を設定できる出力（ZIP 内のファイル名と、ZIP 全体の zip）
+var BudgetTargets = []string{"desktop.js", "mobile.js", "config.js", "desktop.css", "mobile.css", "config.css", "zip"}
+
+// SizeBudget は出力のサイズの上限（"150KB"・"1.5MB" のように指定する）
+type SizeBudget struct {
+	// Warn を超えると警告する
+	Warn string `json:"warn,omitempty"`
+	// Error を超えるとビルドを失敗させる
+	Error string `json:"error,omitempty"`
+}
+
+// sizePattern はサイズの指定（単位は B・KB・MB。1KB = 1024B）
+var sizePattern = regexp.MustCompile(`(?i)^\s*([0-9]+(?:\.[0-9]+)?)\s*(B|KB|MB)?\s*$`)
+
+// ParseSize は "150KB" のようなサイズの指定をバイト数に変換する
+func ParseSize(s string) (int64, error) {
+	m := sizePattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, fmt.Errorf("サイズの指定が不正です: %q（例: 150KB, 1.5MB）", s)
+	}
+	n, _ := strconv.ParseFloat(m[1], 64)
+	switch strings.ToUpper(m[2]) {
+	case "KB":
+		n *= 1 << 10
+	case "MB":
+		n *= 1 << 20
+	}
+	return int64(n), nil
+}
+
+// FormatSize はバイト数を 12.3 KB の形式にする
+func FormatSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
+
+// SizeLimits はバイト数に変換したサイズの上限（0 は上限なし）
+type SizeLimits struct {
+	Warn  int64
+	Error int64
+}
+
+// Budgets は build.budgets をバイト数に変換し、出力名とサイズの指定を検証する
+func (c *Config) Budgets() (map[string]SizeLimits, error) {
+	limits := map[string]SizeLimits{}
+	if c.Build == nil {
+		return limits, nil
+	}
+	for target, budget := range c.Build.Budgets {
+		if !slices.Contains(BudgetTargets, target) {
+			return nil, fmt.Errorf("build.budgets: %s には上限を設定できません（%s）", target, strings.Join(BudgetTargets, ", "))
+		}
+		if budget == nil {
+			continue
+		}
+		var l SizeLimits
+		var err error
+		if budget.Warn != "" {
+			if l.Warn, err = ParseSize(budget.Warn); err != nil {
+				return nil, fmt.Errorf("build.budgets.%s.warn: %w", target, err)
+			}
+		}
+		if budget.Error != "" {
+			if l.Error, err = ParseSize(budget.Error); err != nil {
+				return nil, fmt.Errorf("build.budgets.%s.error: %w", target, err)
+			}
+		}
+		limits[target] = l
+	}
+	return limits, nil
+}
+
+// BuildSizesFile は kpdev build が出力のサイズを記録するファイル（次のビルドとの差分の表示に使う）
+const BuildSizesFile = "build-sizes.json"
+
+// BuildSizes はビルドプロファイル（とバリアント）ごとの前回のビルドのサイズ（.kpdev/build-sizes.json）
+// サイズの推移をチームで共有するため Git で追跡する
+type BuildSizes struct {
+	Builds map[string]*BuildSizeRecord `json:"builds"`
+}
+
+// BuildSizeRecord は1回のビルドの出力のサイズ
+type BuildSizeRecord struct {
+	Version string           `json:"version"`
+	BuiltAt string           `json:"builtAt"`
+	Sizes   map[string]int64 `json:"sizes"`
+}
+
+// BuildSizesKey はサイズを記録するキーを返す（バリアントはプロファイル名:バリアント名）
+// 環境ごとに定数を埋め込むビルドは出力が異なるため、末尾に @環境名 を付けて区別する
+func BuildSizesKey(profile, variant, environment string) string {
+	key := profile
+	if variant != "" {
+		key += ":" + variant
+	}
+	if environment != "" {
+		key += "@" + environment
+	}
+	return key
+}
+
+// LoadBuildSizes は前回のビルドのサイズを読み込む（ファイルがなければ空の状態を返す）
+func LoadBuildSizes(projectDir string) (*BuildSizes, error) {
+	s := &BuildSizes{Builds: map[string]*BuildSizeRecord{}}
+
+	data, err := os.ReadFile(filepath.Join(GetConfigDir(projectDir), BuildSizesFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("%s の解析エラー: %w", BuildSizesFile, err)
+	}
+	if s.Builds == nil {
+		s.Builds = map[string]*BuildSizeRecord{}
+	}
+	return s, nil
+}
+
+// Save はビルドのサイズを保存する
+func (s *BuildSizes) Save(projectDir string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(GetConfigDir(projectDir), BuildSizesFile), append(data, '\n'), 0644)
+}
@@ -2,8 +2,11 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 )
 
 const ConfigDir = ".kpdev"
@@ -20,12 +23,73 @@ type AuthConfig struct {
 type DevEnvConfig struct {
 	Domain string     `json:"domain"`
 	Auth   AuthConfig `json:"auth,omitempty"`
+	// BaseURL を指定すると Domain の代わりに接続する（kpdev mock-server など）
+	BaseURL string `json:"baseUrl,omitempty"`
+	// TestApp は kpdev app create で作成したテストアプリ
+	TestApp *TestAppConfig `json:"testApp,omitempty"`
+}
+
+// TestAppConfig は kpdev app create で作成したテストアプリの情報
+type TestAppConfig struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	CreatedAt string `json:"createdAt"`
 }
 
 type ProdEnvConfig struct {
 	Name   string     `json:"name"`
 	Domain string     `json:"domain"`
 	Auth   AuthConfig `json:"auth,omitempty"`
+	// BaseURL を指定すると Domain の代わりに接続する（kpdev mock-server など）
+	BaseURL string `json:"baseUrl,omitempty"`
+	// Env はこの環境向けにビルドするときの定数（import.meta.env.{キー} として埋め込む）
+	Env map[string]interface{} `json:"env,omitempty"`
+}
+
+// URL は開発環境の接続先URLを返す
+func (e DevEnvConfig) URL() string {
+	return envURL(e.BaseURL, e.Domain)
+}
+
+// URL は本番環境の接続先URLを返す
+func (e ProdEnvConfig) URL() string {
+	return envURL(e.BaseURL, e.Domain)
+}
+
+// envKeyPattern は環境の定数に使えるキー（import.meta.env.{キー} として参照する）
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// EnvDefines は環境の定数を Vite の define（import.meta.env.{キー} → JSON 文字列）に変換する
+func EnvDefines(env map[string]interface{}) (map[string]string, error) {
+	defines := make(map[string]string, len(env))
+	for key, value := range env {
+		if !envKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("環境の定数のキーが不正です: %s（英数字と _ のみ）", key)
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("定数 %s の変換エラー: %w", key, err)
+		}
+		defines["import.meta.env."+key] = string(data)
+	}
+	return defines, nil
+}
+
+// EnvKey は環境の定数の組み合わせを表す文字列を返す（同じ定数の環境は同じ ZIP をデプロイできる）
+func (e ProdEnvConfig) EnvKey() string {
+	if len(e.Env) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(e.Env)
+	return string(data)
+}
+
+// envURL は baseUrl が設定されていればそれを、なければ https://{domain} を返す
+func envURL(baseURL, domain string) string {
+	if baseURL != "" {
+		return strings.TrimRight(baseURL, "/")
+	}
+	return "https://" + domain
 }
 
 type KintoneConfig struct {
@@ -36,6 +100,9 @@ type KintoneConfig struct {
 type EntryConfig struct {
 	Main   string `json:"main"`
 	Config string `json:"config"`
+	// Desktop / Mobile を指定すると、その対象だけ別のエントリーからバンドルする（未指定なら Main を共有）
+	Desktop string `json:"desktop,omitempty"`
+	Mobile  string `json:"mobile,omitempty"`
 }
 
 type DevConfig struct {
@@ -48,11 +115,32 @@ type TargetsConfig struct {
 	Mobile  bool `json:"mobile"`
 }
 
+// BuildConfig は kpdev build の設定
+type BuildConfig struct {
+	// Externals はバンドルせず js.cybozu.com から読み込むパッケージ（react, react-dom など）
+	Externals []string `json:"externals,omitempty"`
+	// Profiles は --mode で指定するビルドプロファイル（prod / pre 以外も定義できる）
+	Profiles map[string]*BuildProfile `json:"profiles,omitempty"`
+	// AssetsBaseURL を指定するとビルド後の assetUrl() がこの URL を参照する（省略時はプラグインの assets/）
+	AssetsBaseURL string `json:"assetsBaseUrl,omitempty"`
+	// Budgets は出力ごとのサイズの上限（desktop.js・mobile.js・config.js・CSS・zip）
+	Budgets map[string]*SizeBudget `json:"budgets,omitempty"`
+}
+
+// AssetsBaseURL はビルド後の assetUrl() が参照する URL を返す（空ならプラグインの assets/）
+func (c *Config) AssetsBaseURL() string {
+	if c.Build == nil {
+		return ""
+	}
+	return c.Build.AssetsBaseURL
+}
+
 type Config struct {
 	SchemaVersion  int           `json:"schemaVersion,omitempty"`
 	Kintone        KintoneConfig `json:"kintone"`
 	Dev            DevConfig     `json:"dev"`
 	Targets        TargetsConfig `json:"targets"`
+	Build          *BuildConfig  `json:"build,omitempty"`
 	PackageManager string        `json:"packageManager,omitempty"`
 }
 
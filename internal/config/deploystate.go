@@ -0,0 +1,75 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// DeployStateFile は kpdev deploy が環境ごとのデプロイ結果を記録するファイル
+const DeployStateFile = "deploy-state.json"
+
+// DeployState は環境ごとの最後のデプロイ結果（.kpdev/deploy-state.json）
+// チームで共有するため Git で追跡する
+type DeployState struct {
+	Environments map[string]*DeployedPlugin `json:"environments"`
+}
+
+// DeployedPlugin は環境にデプロイしたプラグインの情報
+type DeployedPlugin struct {
+	Version        string   `json:"version"`
+	RequiredParams []string `json:"requiredParams,omitempty"`
+	DeployedAt     string   `json:"deployedAt"`
+	// Artifact はデプロイした ZIP のファイル名
+	Artifact string `json:"artifact,omitempty"`
+	// Env はデプロイした ZIP に埋め込んだ環境の定数
+	Env map[string]interface{} `json:"env,omitempty"`
+}
+
+// LoadDeployState はデプロイ結果を読み込む（ファイルがなければ空の状態を返す）
+func LoadDeployState(projectDir string) (*DeployState, error) {
+	state := &DeployState{Environments: map[string]*DeployedPlugin{}}
+
+	data, err := os.ReadFile(filepath.Join(GetConfigDir(projectDir), DeployStateFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if state.Environments == nil {
+		state.Environments = map[string]*DeployedPlugin{}
+	}
+	return state, nil
+}
+
+// Save はデプロイ結果を保存する
+func (s *DeployState) Save(projectDir string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(GetConfigDir(projectDir), DeployStateFile), append(data, '\n'), 0644)
+}
+
+// ManifestRequiredParams は manifest.json の required_params を返す
+// config 内を優先し、トップレベルもフォールバックとして扱う
+func ManifestRequiredParams(manifest map[string]interface{}) []string {
+	raw, _ := manifest["required_params"].([]interface{})
+	if c, ok := manifest["config"].(map[string]interface{}); ok {
+		if params, ok := c["required_params"].([]interface{}); ok {
+			raw = params
+		}
+	}
+
+	var params []string
+	for _, p := range raw {
+		if s, ok := p.(string); ok {
+			params = append(params, s)
+		}
+	}
+	return params
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TargetBundle は desktop / mobile の JS・CSS をどのバンドルから作るかを返す
+// 個別のエントリーが指定されていればその対象名、なければ共有の "main"
+func (c *Config) TargetBundle(target string) string {
+	switch {
+	case target == "desktop" && c.Dev.Entry.Desktop != "":
+		return "desktop"
+	case target == "mobile" && c.Dev.Entry.Mobile != "":
+		return "mobile"
+	}
+	return "main"
+}
+
+// Bundles はビルドするバンドル名とエントリー（プロジェクトルートからの / 始まりのパス）を返す
+// main は desktop / mobile のどちらかが共有している場合のみ含める
+func (c *Config) Bundles() map[string]string {
+	bundles := map[string]string{"config": c.Dev.Entry.Config}
+	targets := map[string]bool{"desktop": c.Targets.Desktop, "mobile": c.Targets.Mobile}
+	for _, target := range []string{"desktop", "mobile"} {
+		if !targets[target] {
+			continue
+		}
+		switch bundle := c.TargetBundle(target); bundle {
+		case "desktop":
+			bundles[bundle] = c.Dev.Entry.Desktop
+		case "mobile":
+			bundles[bundle] = c.Dev.Entry.Mobile
+		default:
+			bundles[bundle] = c.Dev.Entry.Main
+		}
+	}
+	return bundles
+}
+
+// CheckEntries はビルドするエントリーのファイルが存在するかを確認する
+func (c *Config) CheckEntries(projectDir string) error {
+	for name, entry := range c.Bundles() {
+		if entry == "" {
+			return fmt.Errorf("%s のエントリーが設定されていません（.kpdev/config.json の dev.entry）", name)
+		}
+		p := filepath.Join(projectDir, filepath.FromSlash(strings.TrimPrefix(entry, "/")))
+		if _, err := os.Stat(p); err != nil {
+			return fmt.Errorf("%s のエントリー %s が見つかりません", name, entry)
+		}
+	}
+	return nil
+}
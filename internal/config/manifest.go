@@ -2,6 +2,10 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
 	"strings"
 )
 
@@ -59,3 +63,106 @@ func writeJSONField(sb *strings.Builder, key string, val interface{}, indent str
 	sb.WriteString("\": ")
 	sb.WriteString(jsonStr)
 }
+
+// ManifestExternalURLs は desktop / mobile / config の js・css に指定された外部URL（https://）を順に返す
+// kpdev adopt で移行元の manifest.json から CDN などを引き継ぐときに使う
+func ManifestExternalURLs(manifest map[string]interface{}, target, kind string) []string {
+	var urls []string
+	for _, e := range manifestEntries(manifest, target, kind) {
+		if strings.HasPrefix(e, "https://") {
+			urls = append(urls, e)
+		}
+	}
+	return urls
+}
+
+// BundleResourcePath は kpdev がビルドするバンドルの manifest.json 上のパス（js/desktop.js など）を返す
+func BundleResourcePath(target, kind string) string {
+	return kind + "/" + target + "." + kind
+}
+
+// IsExternalResource は manifest.json のリソースがURLかどうかを返す
+func IsExternalResource(entry string) bool {
+	return strings.HasPrefix(entry, "https://") || strings.HasPrefix(entry, "http://")
+}
+
+// ManifestResources は desktop / mobile / config の js・css に追加したリソース（外部URL・ローカルファイル）を、
+// kpdev のバンドル（BundleResourcePath）より前に読み込むものと後に読み込むものに分けて返す
+// バンドルの記述がなければすべてバンドルより前に読み込む
+// ローカルファイルはプロジェクトルートからの相対パスで、ZIP にも同じパスで格納する
+func ManifestResources(manifest map[string]interface{}, target, kind string) (before, after []string) {
+	bundle := BundleResourcePath(target, kind)
+	found := false
+	for _, e := range manifestEntries(manifest, target, kind) {
+		switch {
+		case e == bundle:
+			found = true
+		case found:
+			after = append(after, e)
+		default:
+			before = append(before, e)
+		}
+	}
+	return before, after
+}
+
+// ManifestLocalResources は ManifestResources のうちローカルファイルを重複なく返す
+func ManifestLocalResources(manifest map[string]interface{}, targets []string) []string {
+	seen := map[string]bool{}
+	var files []string
+	for _, target := range targets {
+		for _, kind := range []string{"js", "css"} {
+			before, after := ManifestResources(manifest, target, kind)
+			for _, e := range append(before, after...) {
+				if !IsExternalResource(e) && !seen[e] {
+					seen[e] = true
+					files = append(files, e)
+				}
+			}
+		}
+	}
+	return files
+}
+
+// ValidateManifestResource は manifest.json に追加したリソースを検証する
+// ローカルファイルはプロジェクト内に存在し、kpdev が生成するファイルと重ならないこと
+func ValidateManifestResource(projectDir, entry string, reserved map[string]bool) error {
+	if IsExternalResource(entry) {
+		if !strings.HasPrefix(entry, "https://") {
+			return fmt.Errorf("%s: kintone のプラグインでは https のURLのみ読み込めます", entry)
+		}
+		return nil
+	}
+
+	clean := path.Clean(entry)
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return fmt.Errorf("%s: ローカルファイルはプロジェクトルートからの相対パスで指定してください", entry)
+	}
+	if clean != entry {
+		return fmt.Errorf("%s: %s のように指定してください", entry, clean)
+	}
+	if reserved[clean] {
+		return fmt.Errorf("%s: kpdev が生成するファイルと同じパスは指定できません", entry)
+	}
+	info, err := os.Stat(filepath.Join(projectDir, filepath.FromSlash(clean)))
+	if err != nil || info.IsDir() {
+		return fmt.Errorf("%s: ファイルが見つかりません", entry)
+	}
+	return nil
+}
+
+func manifestEntries(manifest map[string]interface{}, target, kind string) []string {
+	t, ok := manifest[target].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	raw, _ := t[kind].([]interface{})
+
+	var entries []string
+	for _, e := range raw {
+		if s, ok := e.(string); ok {
+			entries = append(entries, s)
+		}
+	}
+	return entries
+}
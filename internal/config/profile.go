@@ -0,0 +1,186 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// DefaultOutputPattern はプラグインZIPの既定のファイル名
+const DefaultOutputPattern = "{name}-{profile}-v{version}.zip"
+
+// BuildProfile は config.json の build.profiles に定義するビルドプロファイル
+// 省略した項目は prod と同じ（prod / pre を定義した場合は組み込みの設定を上書きする）
+type BuildProfile struct {
+	Description   string `json:"description,omitempty"`
+	Minify        *bool  `json:"minify,omitempty"`
+	RemoveConsole *bool  `json:"removeConsole,omitempty"`
+	// NamePrefix はプラグイン名の先頭に付ける文字列（ja / en）
+	NamePrefix *NamePrefix `json:"namePrefix,omitempty"`
+	// Key は署名に使う鍵（"prod"・"dev"、またはプロジェクトルートからの秘密鍵のパス）
+	Key string `json:"key,omitempty"`
+	// Sourcemap は "inline"（バンドルに埋め込む）または "hidden"（dist/sourcemaps/ に出力し ZIP には含めない）
+	Sourcemap string `json:"sourcemap,omitempty"`
+	// Define はビルド時に置き換える定数（キー → JSON の値）
+	Define map[string]interface{} `json:"define,omitempty"`
+	// Output はプラグインZIPのファイル名（{name}・{profile}・{version} を置き換える）
+	Output string `json:"output,omitempty"`
+}
+
+// NamePrefix はプラグイン名の接頭辞
+type NamePrefix struct {
+	Ja string `json:"ja,omitempty"`
+	En string `json:"en,omitempty"`
+}
+
+// ResolvedProfile は組み込みの設定と config.json をまとめたビルドプロファイル
+type ResolvedProfile struct {
+	Name          string
+	Description   string
+	Minify        bool
+	RemoveConsole bool
+	NamePrefix    NamePrefix
+	Key           string
+	Sourcemap     string
+	Define        map[string]interface{}
+	Output        string
+}
+
+// builtinProfiles は組み込みのプロファイル
+var builtinProfiles = map[string]ResolvedProfile{
+	"prod": {
+		Name:          "prod",
+		Description:   "本番ビルド (minify + console削除)",
+		Minify:        true,
+		RemoveConsole: true,
+		Key:           "prod",
+	},
+	"pre": {
+		Name:          "pre",
+		Description:   "プレビルド (minifyなし + console残す + 名前に[開発]付与)",
+		Minify:        false,
+		RemoveConsole: false,
+		NamePrefix:    NamePrefix{Ja: "[開発] ", En: "[DEV] "},
+		Key:           "dev",
+	},
+}
+
+// ProfileNames はビルドプロファイルの名前を返す（prod・pre の後に config.json のプロファイルを名前順）
+func (c *Config) ProfileNames() []string {
+	names := []string{"prod", "pre"}
+	var custom []string
+	if c.Build != nil {
+		for name := range c.Build.Profiles {
+			if _, ok := builtinProfiles[name]; !ok {
+				custom = append(custom, name)
+			}
+		}
+	}
+	sort.Strings(custom)
+	return append(names, custom...)
+}
+
+// Profile は名前からビルドプロファイルを解決する
+func (c *Config) Profile(name string) (*ResolvedProfile, error) {
+	var custom *BuildProfile
+	if c.Build != nil {
+		custom = c.Build.Profiles[name]
+	}
+
+	base, builtin := builtinProfiles[name]
+	if !builtin {
+		if custom == nil {
+			return nil, fmt.Errorf("ビルドプロファイルが見つかりません: %s（%s）", name, strings.Join(c.ProfileNames(), ", "))
+		}
+		base = builtinProfiles["prod"]
+		base.Name = name
+		base.Description = ""
+	}
+	p := base
+	if p.Output == "" {
+		p.Output = DefaultOutputPattern
+	}
+	if custom == nil {
+		return &p, nil
+	}
+
+	if custom.Description != "" {
+		p.Description = custom.Description
+	}
+	if custom.Minify != nil {
+		p.Minify = *custom.Minify
+	}
+	if custom.RemoveConsole != nil {
+		p.RemoveConsole = *custom.RemoveConsole
+	}
+	if custom.NamePrefix != nil {
+		p.NamePrefix = *custom.NamePrefix
+	}
+	if custom.Key != "" {
+		p.Key = custom.Key
+	}
+	if custom.Sourcemap != "" {
+		if !slices.Contains([]string{"inline", "hidden"}, custom.Sourcemap) {
+			return nil, fmt.Errorf("ビルドプロファイル %s: sourcemap は inline または hidden を指定してください", name)
+		}
+		p.Sourcemap = custom.Sourcemap
+	}
+	if len(custom.Define) > 0 {
+		p.Define = custom.Define
+	}
+	if custom.Output != "" {
+		if !strings.HasSuffix(custom.Output, ".zip") || strings.ContainsAny(custom.Output, `/\`) {
+			return nil, fmt.Errorf("ビルドプロファイル %s: output は .zip で終わるファイル名を指定してください", name)
+		}
+		p.Output = custom.Output
+	}
+	return &p, nil
+}
+
+// Summary はプロファイルの設定を1行で返す（モードの選択肢などに表示する）
+func (p *ResolvedProfile) Summary() string {
+	if p.Description != "" {
+		return p.Description
+	}
+	var parts []string
+	if p.Minify {
+		parts = append(parts, "minify")
+	} else {
+		parts = append(parts, "minifyなし")
+	}
+	if p.RemoveConsole {
+		parts = append(parts, "console削除")
+	} else {
+		parts = append(parts, "console残す")
+	}
+	if p.NamePrefix.Ja != "" || p.NamePrefix.En != "" {
+		parts = append(parts, "名前に"+strings.TrimSpace(p.NamePrefix.Ja)+"付与")
+	}
+	if p.Sourcemap != "" {
+		parts = append(parts, "sourcemap "+p.Sourcemap)
+	}
+	if p.Key != "prod" {
+		parts = append(parts, "鍵 "+p.Key)
+	}
+	return strings.Join(parts, " + ")
+}
+
+// DefineJSON は define の値を Vite の define に渡せる JSON 文字列に変換する
+func DefineJSON(define map[string]interface{}) (map[string]string, error) {
+	defines := make(map[string]string, len(define))
+	for key, value := range define {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("define %s の変換エラー: %w", key, err)
+		}
+		defines[key] = string(data)
+	}
+	return defines, nil
+}
+
+// OutputName はプラグインZIPのファイル名を返す
+func (p *ResolvedProfile) OutputName(name, version string) string {
+	return strings.NewReplacer("{name}", name, "{profile}", p.Name, "{version}", version).Replace(p.Output)
+}
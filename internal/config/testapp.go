@@ -0,0 +1,59 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// TestAppFile は kpdev app create が読み込む既定のスキーマファイル
+const TestAppFile = "test-app.json"
+
+// TestAppSchema はテストアプリの宣言的な定義（.kpdev/test-app.json）
+type TestAppSchema struct {
+	Name string `json:"name"`
+	// Fields は /k/v1/preview/app/form/fields.json の properties と同じ形式
+	Fields map[string]map[string]interface{} `json:"fields"`
+	// Records は登録するレコードのフィクスチャファイル（スキーマファイルからの相対パス）
+	Records string `json:"records,omitempty"`
+}
+
+// LoadTestAppSchema はテストアプリのスキーマを読み込んで検証する
+func LoadTestAppSchema(path string) (*TestAppSchema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var schema TestAppSchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("%s の解析エラー: %w", filepath.Base(path), err)
+	}
+
+	if schema.Name == "" {
+		return nil, fmt.Errorf("%s: name を指定してください", filepath.Base(path))
+	}
+	if len(schema.Fields) == 0 {
+		return nil, fmt.Errorf("%s: fields を1つ以上指定してください", filepath.Base(path))
+	}
+	for code, field := range schema.Fields {
+		if t, _ := field["type"].(string); t == "" {
+			return nil, fmt.Errorf("%s: フィールド %s に type がありません", filepath.Base(path), code)
+		}
+		// code はキーと同じ値を既定にする
+		if _, ok := field["code"]; !ok {
+			field["code"] = code
+		}
+		if _, ok := field["label"]; !ok {
+			field["label"] = code
+		}
+	}
+
+	// フィクスチャのパスはスキーマファイルからの相対パスで解決
+	if schema.Records != "" && !filepath.IsAbs(schema.Records) {
+		schema.Records = filepath.Join(filepath.Dir(path), schema.Records)
+	}
+
+	return &schema, nil
+}
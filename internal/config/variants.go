@@ -0,0 +1,130 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// VariantsFile はホワイトラベル版（バリアント）の定義と、バリアントごとのプラグインIDを記録するファイル
+const VariantsFile = "variants.json"
+
+// variantNamePattern はバリアント名に使える文字（ZIP・鍵のファイル名に使う）
+var variantNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// variantManifestKeys はバリアントで上書きできる manifest.json のキー
+var variantManifestKeys = []string{"name", "description", "homepage_url"}
+
+// Variants は .kpdev/variants.json の内容
+// パートナーごとの定義とプラグインIDをチームで共有するため Git で追跡する
+type Variants struct {
+	Variants map[string]*Variant `json:"variants"`
+}
+
+// Variant は同じコードから名前・アイコン・プラグインIDを変えてビルドするホワイトラベル版
+type Variant struct {
+	Name string `json:"-"`
+	// Manifest は manifest.json に重ねる値（name・description・homepage_url。言語ごとにマージする）
+	Manifest map[string]interface{} `json:"manifest,omitempty"`
+	// Icon はプロジェクトルートからのアイコンのパス（省略時は icon.png）
+	Icon string `json:"icon,omitempty"`
+	// Key はプロジェクトルートからの秘密鍵のパス（省略時は .kpdev/keys/private.{バリアント名}.ppk を生成）
+	Key string `json:"key,omitempty"`
+	// Define はビルド時に置き換える定数（ビルドプロファイルの define より優先）
+	Define map[string]interface{} `json:"define,omitempty"`
+	// PluginID は kpdev build が秘密鍵から求めて記録する
+	PluginID string `json:"pluginId,omitempty"`
+}
+
+// LoadVariants はバリアントの定義を読み込む（ファイルがなければ空の状態を返す）
+func LoadVariants(projectDir string) (*Variants, error) {
+	v := &Variants{Variants: map[string]*Variant{}}
+
+	data, err := os.ReadFile(filepath.Join(GetConfigDir(projectDir), VariantsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return v, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, fmt.Errorf("%s の解析エラー: %w", VariantsFile, err)
+	}
+	if v.Variants == nil {
+		v.Variants = map[string]*Variant{}
+	}
+	for name, variant := range v.Variants {
+		if variant == nil {
+			variant = &Variant{}
+			v.Variants[name] = variant
+		}
+		variant.Name = name
+	}
+	return v, nil
+}
+
+// Save はバリアントの定義を保存する
+func (v *Variants) Save(projectDir string) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(GetConfigDir(projectDir), VariantsFile), append(data, '\n'), 0644)
+}
+
+// Names はバリアント名を名前順に返す
+func (v *Variants) Names() []string {
+	return slices.Sorted(maps.Keys(v.Variants))
+}
+
+// Get は名前からバリアントを返し、定義を検証する
+func (v *Variants) Get(name string) (*Variant, error) {
+	variant, ok := v.Variants[name]
+	if !ok {
+		if len(v.Variants) == 0 {
+			return nil, fmt.Errorf("バリアントが見つかりません: %s（.kpdev/%s にバリアントが定義されていません）", name, VariantsFile)
+		}
+		return nil, fmt.Errorf("バリアントが見つかりません: %s（%s）", name, strings.Join(v.Names(), ", "))
+	}
+	if !variantNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("バリアント名が不正です: %s（英小文字・数字・_・- のみ）", name)
+	}
+	for key := range variant.Manifest {
+		if !slices.Contains(variantManifestKeys, key) {
+			return nil, fmt.Errorf("バリアント %s: manifest で上書きできるのは %s です（%s）", name, strings.Join(variantManifestKeys, "・"), key)
+		}
+	}
+	return variant, nil
+}
+
+// KeyPath はバリアントの署名に使う秘密鍵のパスを返す
+func (v *Variant) KeyPath(projectDir string) string {
+	if v.Key == "" {
+		return filepath.Join(GetConfigDir(projectDir), "keys", "private."+v.Name+".ppk")
+	}
+	if filepath.IsAbs(v.Key) {
+		return v.Key
+	}
+	return filepath.Join(projectDir, filepath.FromSlash(v.Key))
+}
+
+// ApplyManifest はバリアントの manifest を manifest.json に重ねる
+// name・description・homepage_url は言語ごとにマージする（指定しなかった言語は元の値を残す）
+func (v *Variant) ApplyManifest(manifest map[string]interface{}) {
+	for key, value := range v.Manifest {
+		overlay, ok := value.(map[string]interface{})
+		base, baseOK := manifest[key].(map[string]interface{})
+		if !ok || !baseOK {
+			manifest[key] = value
+			continue
+		}
+		merged := maps.Clone(base)
+		maps.Copy(merged, overlay)
+		manifest[key] = merged
+	}
+}
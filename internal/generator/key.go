@@ -7,6 +7,7 @@ import (
 	"crypto/x509"
 	"encoding/hex"
 	"encoding/pem"
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -44,6 +45,22 @@ func GenerateKeys(projectDir string) error {
 	return nil
 }
 
+// EnsureKeyFile は秘密鍵がなければ生成する（生成した場合は true を返す）
+func EnsureKeyFile(path string) (bool, error) {
+	if _, err := os.Stat(path); err == nil {
+		return false, nil
+	} else if !os.IsNotExist(err) {
+		return false, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	if err := generateKeyFile(path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 func generateKeyFile(path string) error {
 	privateKey, err := rsa.GenerateKey(rand.Reader, KeyBits)
 	if err != nil {
@@ -66,20 +83,62 @@ func LoadPrivateKey(path string) (*rsa.PrivateKey, error) {
 
 	block, _ := pem.Decode(data)
 	if block == nil {
-		return nil, err
+		return nil, fmt.Errorf("%s は PEM 形式の秘密鍵ではありません", filepath.Base(path))
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
 	}
 
-	return x509.ParsePKCS1PrivateKey(block.Bytes)
+	// PKCS#8（BEGIN PRIVATE KEY）形式の RSA 鍵も受け付ける
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s の解析エラー: %w", filepath.Base(path), err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s は RSA の秘密鍵ではありません", filepath.Base(path))
+	}
+	return key, nil
+}
+
+// ImportProdKey は既存の秘密鍵を本番用の鍵として保存する（kpdev adopt でプラグインIDを維持するため）
+// PKCS#1 の PEM 形式に揃えて保存し、プラグインIDを返す
+func ImportProdKey(projectDir, keyPath string) (string, error) {
+	privateKey, err := LoadPrivateKey(keyPath)
+	if err != nil {
+		return "", err
+	}
+
+	keysDir := filepath.Join(config.GetConfigDir(projectDir), "keys")
+	if err := os.MkdirAll(keysDir, 0755); err != nil {
+		return "", err
+	}
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	})
+	if err := os.WriteFile(GetProdKeyPath(projectDir), keyPEM, 0600); err != nil {
+		return "", err
+	}
+
+	return GeneratePluginID(privateKey)
 }
 
 // GeneratePluginID は秘密鍵からプラグインIDを生成する
-// kintone の仕様に従い、公開鍵の SHA256 ハッシュの先頭32文字を変換
 func GeneratePluginID(privateKey *rsa.PrivateKey) (string, error) {
 	pubKeyDer, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
 	if err != nil {
 		return "", err
 	}
 
+	return PluginIDFromPublicKeyDER(pubKeyDer), nil
+}
+
+// PluginIDFromPublicKeyDER はプラグインZIPの PUBKEY（DER）からプラグインIDを求める
+// kintone の仕様に従い、公開鍵の SHA256 ハッシュの先頭32文字を変換
+func PluginIDFromPublicKeyDER(pubKeyDer []byte) string {
 	hash := sha256.Sum256(pubKeyDer)
 	hexStr := hex.EncodeToString(hash[:])[:32]
 
@@ -93,7 +152,7 @@ func GeneratePluginID(privateKey *rsa.PrivateKey) (string, error) {
 		}
 	}
 
-	return string(result), nil
+	return string(result)
 }
 
 func GetDevKeyPath(projectDir string) string {
@@ -14,8 +14,11 @@ import (
 )
 
 const (
-	LoaderSchemaVersion = 1
+	LoaderSchemaVersion = 4
 	DevOrigin           = "https://localhost:3000"
+
+	// LoaderOwnerPlaceholder はローダーJS内の管理者名（パッケージング時に置換される）
+	LoaderOwnerPlaceholder = `"__KPDEV_OWNER__"`
 )
 
 type LoaderMeta struct {
@@ -91,20 +94,20 @@ func GenerateLoader(projectDir string, answers *prompt.InitAnswers, version stri
 
 	// desktop.js
 	if answers.TargetDesktop {
-		if err := generateLoaderJS(devPluginDir, "desktop"); err != nil {
+		if err := generateLoaderJS(devPluginDir, "desktop", answers.ProjectName); err != nil {
 			return err
 		}
 	}
 
 	// mobile.js
 	if answers.TargetMobile {
-		if err := generateLoaderJS(devPluginDir, "mobile"); err != nil {
+		if err := generateLoaderJS(devPluginDir, "mobile", answers.ProjectName); err != nil {
 			return err
 		}
 	}
 
 	// config-loader.js
-	if err := generateConfigLoaderJS(devPluginDir); err != nil {
+	if err := generateConfigLoaderJS(devPluginDir, answers.ProjectName); err != nil {
 		return err
 	}
 
@@ -148,13 +151,7 @@ func GenerateLoader(projectDir string, answers *prompt.InitAnswers, version stri
 	meta.Files.CertKeyPath = ".kpdev/certs/localhost-key.pem"
 	meta.Files.CertCertPath = ".kpdev/certs/localhost.pem"
 
-	metaData, err := json.MarshalIndent(meta, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	metaPath := filepath.Join(managedDir, "loader.meta.json")
-	return os.WriteFile(metaPath, metaData, 0644)
+	return saveLoaderMeta(projectDir, meta)
 }
 
 func generateDevManifest(dir string, answers *prompt.InitAnswers) error {
@@ -208,46 +205,184 @@ func generateDevManifest(dir string, answers *prompt.InitAnswers) error {
 	return os.WriteFile(filepath.Join(dir, "manifest.json"), data, 0644)
 }
 
-func generateLoaderJS(dir string, target string) error {
+func generateLoaderJS(dir string, target string, projectName string) error {
 	// main-loader.js を生成
-	mainLoaderContent := fmt.Sprintf(`(() => {
-  const origin = "%s";
-  const t = Date.now();
-
-  const xhr = new XMLHttpRequest();
-  xhr.open("GET", origin + "/main.js?t=" + t, false);
-  xhr.send();
-  if (xhr.status === 200) {
-    eval(xhr.responseText);
-  }
-
-  // Vite HMR client をscriptタグで読み込み
-  const script = document.createElement("script");
-  script.type = "module";
-  script.src = origin + "/@vite/client";
-  document.head.appendChild(script);
-})();
-`, DevOrigin)
+	mainLoaderContent := devLoaderScript(projectName, "main", "/main.js")
 
 	if err := os.WriteFile(filepath.Join(dir, "main-loader.js"), []byte(mainLoaderContent), 0644); err != nil {
 		return err
 	}
 
-	// desktop.js / mobile.js は main-loader.js と同じ内容
-	return os.WriteFile(filepath.Join(dir, target+".js"), []byte(mainLoaderContent), 0644)
+	// desktop.js / mobile.js は対象ごとのバンドルを読み込む
+	// 個別のエントリーがなければ Dev server が main のバンドルを返す
+	content := devLoaderScript(projectName, target, "/"+target+".js")
+	return os.WriteFile(filepath.Join(dir, target+".js"), []byte(content), 0644)
+}
+
+func generateConfigLoaderJS(dir string, projectName string) error {
+	content := devLoaderScript(projectName, "config", "/config.js")
+
+	return os.WriteFile(filepath.Join(dir, "config-loader.js"), []byte(content), 0644)
 }
 
-func generateConfigLoaderJS(dir string) error {
-	content := fmt.Sprintf(`(() => {
+// devLoaderScript は Dev server からエントリを読み込むローダーJSを生成する
+// プラグインコード内の未捕捉エラーは /__kpdev/report に転送され、kpdev dev のターミナルに表示される
+// Dev server に接続できない場合は画面下部にバナー、ビルドエラー時はエラー内容を表示する
+func devLoaderScript(projectName, entry, scriptPath string) string {
+	return fmt.Sprintf(`(() => {
   const origin = "%s";
+  const entry = "%s";
+  const project = %s;
+  const owner = %s;
   const t = Date.now();
 
-  // JSをフェッチして実行
-  const xhr = new XMLHttpRequest();
-  xhr.open("GET", origin + "/config.js?t=" + t, false);
-  xhr.send();
+  // エラー転送（Dev server から読み込んだコード内のエラーのみ対象）
+  const isPluginCode = (text) => typeof text === "string" && text.indexOf(origin + "/") !== -1;
+  const report = (level, message, stack, skip) => {
+    const body = JSON.stringify({
+      level: level,
+      entry: entry,
+      message: String(message),
+      stack: stack || "",
+      skip: skip || 0,
+      page: location.href,
+    });
+    try {
+      if (navigator.sendBeacon && navigator.sendBeacon(origin + "/__kpdev/report", body)) {
+        return;
+      }
+      fetch(origin + "/__kpdev/report", { method: "POST", mode: "no-cors", body: body });
+    } catch (e) {
+      // 転送できなくても kintone の動作には影響させない
+    }
+  };
+  window.__kpdev = window.__kpdev || {};
+  window.__kpdev.report = report;
+
+  window.addEventListener("error", (event) => {
+    const stack = event.error && event.error.stack ? event.error.stack : "";
+    if (!isPluginCode(event.filename) && !isPluginCode(stack)) {
+      return;
+    }
+    const at = event.filename ? event.filename + ":" + event.lineno + ":" + event.colno : "";
+    report("uncaught", event.message, stack || at);
+  });
+  window.addEventListener("unhandledrejection", (event) => {
+    const reason = event.reason;
+    const stack = reason && reason.stack ? reason.stack : "";
+    if (!isPluginCode(stack)) {
+      return;
+    }
+    const message = reason && reason.message ? reason.message : String(reason);
+    report("unhandledrejection", "Unhandled rejection: " + message, stack);
+  });
+
+  // kintone の画面上に閉じられるパネルを表示
+  const showPanel = (id, css, build, onClose) => {
+    const render = () => {
+      if (document.getElementById(id)) {
+        return;
+      }
+      const panel = document.createElement("div");
+      panel.id = id;
+      panel.style.cssText = css;
+      build(panel);
+      const close = document.createElement("button");
+      close.type = "button";
+      close.textContent = "\u00d7";
+      close.title = "閉じる";
+      close.style.cssText = "position:absolute;top:6px;right:10px;border:none;background:none;color:inherit;font-size:18px;line-height:1;cursor:pointer;";
+      close.onclick = () => {
+        panel.remove();
+        if (onClose) {
+          onClose();
+        }
+      };
+      panel.appendChild(close);
+      document.body.appendChild(panel);
+    };
+    if (document.body) {
+      render();
+    } else {
+      document.addEventListener("DOMContentLoaded", render);
+    }
+  };
+  const appendText = (parent, tag, text, css) => {
+    const el = document.createElement(tag);
+    el.textContent = text;
+    if (css) {
+      el.style.cssText = css;
+    }
+    parent.appendChild(el);
+  };
+
+  // Dev server に接続できない場合のバナー（同じテナントを使う他のメンバー向け）
+  const offlineKey = "kpdev-offline-dismissed:" + project;
+  const showOfflineBanner = () => {
+    try {
+      if (sessionStorage.getItem(offlineKey)) {
+        return;
+      }
+    } catch (e) {
+      // sessionStorage が使えない場合は毎回表示
+    }
+    const ownerName = owner && owner.indexOf("__KPDEV_") !== 0 ? owner : "不明";
+    showPanel("kpdev-offline-banner",
+      "position:fixed;left:16px;right:16px;bottom:16px;z-index:10000;padding:10px 40px 10px 14px;border-radius:6px;background:#fff8e1;color:#5d4200;border:1px solid #f0c36d;font:13px/1.6 sans-serif;box-shadow:0 2px 8px rgba(0,0,0,0.15);",
+      (panel) => {
+        appendText(panel, "strong", "[DEV] " + project + " の開発サーバーに接続できないため、プラグインは動作していません");
+        appendText(panel, "div", "この開発用プラグインは " + ownerName + " が管理しています。プロジェクトで kpdev dev を実行すると " + origin + " で起動します。");
+      },
+      () => {
+        try {
+          sessionStorage.setItem(offlineKey, "1");
+        } catch (e) {
+          // 無視
+        }
+      });
+  };
+
+  // ビルドエラーの表示
+  const showBuildError = (text) => {
+    let error = { message: text };
+    try {
+      error = JSON.parse(text);
+    } catch (e) {
+      // JSON 以外はそのまま表示
+    }
+    showPanel("kpdev-build-error",
+      "position:fixed;left:24px;right:24px;top:24px;max-height:80vh;overflow:auto;z-index:10001;padding:16px 40px 16px 16px;border-radius:6px;background:#1e1e1e;color:#f8f8f2;border-top:4px solid #e5484d;font:13px/1.5 monospace;box-shadow:0 4px 16px rgba(0,0,0,0.3);",
+      (panel) => {
+        appendText(panel, "strong", "[DEV] " + project + " のビルドに失敗しました", "color:#ff8383;font:bold 14px sans-serif;");
+        if (error.loc && error.loc.file) {
+          appendText(panel, "div", error.loc.file + ":" + error.loc.line + ":" + error.loc.column, "margin-top:8px;color:#8ab4f8;");
+        } else if (error.id) {
+          appendText(panel, "div", error.id, "margin-top:8px;color:#8ab4f8;");
+        }
+        appendText(panel, "pre", error.message || "", "margin:8px 0 0;white-space:pre-wrap;");
+        if (error.frame) {
+          appendText(panel, "pre", error.frame, "margin:8px 0 0;padding:8px;background:#2d2d2d;white-space:pre;overflow:auto;");
+        }
+        appendText(panel, "div", "ソースを修正すると自動で再読み込みされます", "margin-top:8px;color:#999;font-family:sans-serif;");
+      });
+  };
+
+  // JSをフェッチして実行（sourceURL でスタックトレースに Dev server のURLが出るようにする）
+  let xhr = new XMLHttpRequest();
+  try {
+    xhr.open("GET", origin + "%s?t=" + t, false);
+    xhr.send();
+  } catch (e) {
+    xhr = null;
+  }
+  if (!xhr || xhr.status === 0) {
+    showOfflineBanner();
+    return;
+  }
   if (xhr.status === 200) {
-    eval(xhr.responseText);
+    eval(xhr.responseText + "\n//# sourceURL=" + origin + "%s");
+  } else if (xhr.status === 500) {
+    showBuildError(xhr.responseText);
   }
 
   // Vite HMR client をscriptタグで読み込み
@@ -256,9 +391,120 @@ func generateConfigLoaderJS(dir string) error {
   script.src = origin + "/@vite/client";
   document.head.appendChild(script);
 })();
-`, DevOrigin)
+`, DevOrigin, entry, jsString(projectName), LoaderOwnerPlaceholder, scriptPath, scriptPath)
+}
 
-	return os.WriteFile(filepath.Join(dir, "config-loader.js"), []byte(content), 0644)
+// jsString は文字列を JavaScript の文字列リテラルにする
+func jsString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// SyncLoaderResources は .kpdev/manifest.json に追加したリソース（外部URL・ローカルファイル）を
+// 開発用プラグインの manifest.json に反映し、ZIP に格納するローカルファイルを返す
+// config は html を保持したまま js・css だけを更新する
+// 本番ビルドと同じく、バンドルの前後に指定したリソースはローダーJSの前後に読み込む
+func SyncLoaderResources(projectDir string) ([]string, error) {
+	srcData, err := os.ReadFile(filepath.Join(config.GetConfigDir(projectDir), "manifest.json"))
+	if err != nil {
+		return nil, err
+	}
+	var src map[string]interface{}
+	if err := json.Unmarshal(srcData, &src); err != nil {
+		return nil, fmt.Errorf("manifest.json の解析エラー: %w", err)
+	}
+
+	devPluginDir := filepath.Join(config.GetConfigDir(projectDir), "managed", "dev-plugin")
+	devManifestPath := filepath.Join(devPluginDir, "manifest.json")
+	devData, err := os.ReadFile(devManifestPath)
+	if err != nil {
+		return nil, err
+	}
+	var devManifest map[string]interface{}
+	if err := json.Unmarshal(devData, &devManifest); err != nil {
+		return nil, fmt.Errorf("開発用プラグインの manifest.json の解析エラー: %w", err)
+	}
+
+	// 開発用プラグインに含まれるファイルと同じパスは使えない
+	reserved := map[string]bool{}
+	entries, err := os.ReadDir(devPluginDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		reserved[e.Name()] = true
+	}
+
+	var targets []string
+	for _, target := range []string{"desktop", "mobile", "config"} {
+		t, ok := devManifest[target].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		targets = append(targets, target)
+
+		loaderJS := target + ".js"
+		if target == "config" {
+			loaderJS = "config-loader.js"
+		}
+		for _, kind := range []string{"js", "css"} {
+			before, after := config.ManifestResources(src, target, kind)
+			for _, e := range append(before, after...) {
+				if err := config.ValidateManifestResource(projectDir, e, reserved); err != nil {
+					return nil, fmt.Errorf("manifest.json の %s.%s: %w", target, kind, err)
+				}
+			}
+
+			list := before
+			if kind == "js" {
+				list = append(list, loaderJS)
+			}
+			list = append(list, after...)
+			if len(list) > 0 {
+				t[kind] = list
+			} else {
+				delete(t, kind)
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(devManifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if string(data) != string(devData) {
+		if err := os.WriteFile(devManifestPath, data, 0644); err != nil {
+			return nil, err
+		}
+	}
+	return config.ManifestLocalResources(src, targets), nil
+}
+
+// RegenerateLoaderScripts は loader.meta.json をもとにローダーJSを再生成する
+// LoaderSchemaVersion を上げた場合に kpdev migrate から呼ばれる
+func RegenerateLoaderScripts(projectDir string) error {
+	meta, err := LoadLoaderMeta(projectDir)
+	if err != nil {
+		return err
+	}
+
+	devPluginDir := filepath.Join(config.GetConfigDir(projectDir), "managed", "dev-plugin")
+	if meta.Targets.Desktop {
+		if err := generateLoaderJS(devPluginDir, "desktop", meta.Project.Name); err != nil {
+			return err
+		}
+	}
+	if meta.Targets.Mobile {
+		if err := generateLoaderJS(devPluginDir, "mobile", meta.Project.Name); err != nil {
+			return err
+		}
+	}
+	if err := generateConfigLoaderJS(devPluginDir, meta.Project.Name); err != nil {
+		return err
+	}
+
+	meta.SchemaVersion = LoaderSchemaVersion
+	return saveLoaderMeta(projectDir, meta)
 }
 
 func generateConfigHTML(projectDir, devPluginDir string) error {
@@ -295,6 +541,16 @@ func LoadLoaderMeta(projectDir string) (*LoaderMeta, error) {
 	return &meta, nil
 }
 
+func saveLoaderMeta(projectDir string, meta *LoaderMeta) error {
+	metaData, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	metaPath := filepath.Join(config.GetConfigDir(projectDir), "managed", "loader.meta.json")
+	return os.WriteFile(metaPath, metaData, 0644)
+}
+
 func ComputeFileSHA256(path string) (string, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
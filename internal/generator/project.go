@@ -9,6 +9,7 @@ import (
 	"image/png"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/kintone/kpdev/internal/config"
 	"github.com/kintone/kpdev/internal/prompt"
@@ -167,6 +168,39 @@ type manifestTarget struct {
 	CSS []string `json:"css,omitempty"`
 }
 
+// MergePackageDependencies は生成済みの package.json に依存パッケージとバージョンを反映する
+// kpdev adopt で既存プラグインの dependencies を引き継ぐために使う（既存の指定は上書きしない）
+func MergePackageDependencies(projectDir string, deps map[string]string, version string) error {
+	path := filepath.Join(projectDir, "package.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var pkg packageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return err
+	}
+
+	if pkg.Dependencies == nil {
+		pkg.Dependencies = map[string]string{}
+	}
+	for name, v := range deps {
+		if _, ok := pkg.Dependencies[name]; !ok {
+			pkg.Dependencies[name] = v
+		}
+	}
+	if version != "" {
+		pkg.Version = version
+	}
+
+	out, err := json.MarshalIndent(pkg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, 0644)
+}
+
 // GenerateManifest generates .kpdev/manifest.json
 func GenerateManifest(projectDir string, answers *prompt.InitAnswers) error {
 	// プラグイン名（デフォルトはプロジェクト名）
@@ -431,6 +465,8 @@ dist/
 # kpdev managed files
 .kpdev/config.json
 .kpdev/certs/
+.kpdev/preview/
+.kpdev/cache/
 
 # IDE
 .idea/
@@ -445,6 +481,51 @@ Thumbs.db
 	return os.WriteFile(filepath.Join(projectDir, ".gitignore"), []byte(content), 0644)
 }
 
+// GitignoreEntries は kpdev が管理する .gitignore のエントリ（migrate で追記する対象）
+var GitignoreEntries = []string{
+	".kpdev/config.json",
+	".kpdev/certs/",
+	".kpdev/preview/",
+	".kpdev/cache/",
+}
+
+// MissingGitignoreEntries は .gitignore に含まれていない kpdev 管理エントリを返す
+func MissingGitignoreEntries(projectDir string) []string {
+	data, err := os.ReadFile(filepath.Join(projectDir, ".gitignore"))
+	if err != nil {
+		return nil
+	}
+
+	existing := make(map[string]bool)
+	for _, line := range strings.Split(string(data), "\n") {
+		existing[strings.TrimSpace(line)] = true
+	}
+
+	var missing []string
+	for _, entry := range GitignoreEntries {
+		if !existing[entry] {
+			missing = append(missing, entry)
+		}
+	}
+	return missing
+}
+
+// AppendGitignoreEntries は .gitignore の末尾にエントリを追記する
+func AppendGitignoreEntries(projectDir string, entries []string) error {
+	path := filepath.Join(projectDir, ".gitignore")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += "\n# kpdev managed files (migrate)\n" + strings.Join(entries, "\n") + "\n"
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
 func generateTSConfig(projectDir string, answers *prompt.InitAnswers) error {
 	compilerOptions := map[string]interface{}{
 		"target":                 "ES2020",
@@ -0,0 +1,166 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kintone/kpdev/internal/kintone"
+)
+
+// TypesDir はアプリのレコード型を出力するディレクトリ
+const TypesDir = "src/types"
+
+// RecordTypesPath はアプリのレコード型ファイルのパスを返す
+func RecordTypesPath(projectDir, appID string) string {
+	return filepath.Join(projectDir, TypesDir, "kintone-app-"+appID+".ts")
+}
+
+// recordTypeName はアプリIDから型名の接頭辞を返す
+func recordTypeName(appID string) string {
+	return "App" + appID
+}
+
+// userEntityType は USER_SELECT などの値の要素型
+const userEntityType = "{ code: string; name: string }"
+
+// GenerateRecordTypes はフィールド設定から TypeScript のレコード型を生成する
+// 同じフィールド設定からは常に同じ内容を返す（types pull --check で比較するため）
+func GenerateRecordTypes(appID string, fields map[string]kintone.FieldProperty) string {
+	name := recordTypeName(appID)
+
+	var sb strings.Builder
+	sb.WriteString("// このファイルは kpdev types pull で自動生成されています。直接編集しないでください。\n")
+	fmt.Fprintf(&sb, "// app: %s\n\n", appID)
+
+	fmt.Fprintf(&sb, "/** アプリ %s のレコード（フォームのフィールド） */\n", appID)
+	fmt.Fprintf(&sb, "export interface %sRecord {\n", name)
+	writeFieldTypes(&sb, fields, "  ")
+	sb.WriteString("}\n\n")
+
+	fmt.Fprintf(&sb, "/** 保存済みのレコード（$id・$revision を含む） */\n")
+	fmt.Fprintf(&sb, "export interface %sSavedRecord extends %sRecord {\n", name, name)
+	sb.WriteString("  $id: { type: '__ID__'; value: string }\n")
+	sb.WriteString("  $revision: { type: '__REVISION__'; value: string }\n")
+	sb.WriteString("}\n\n")
+
+	fmt.Fprintf(&sb, "/** フィールドコード */\n")
+	fmt.Fprintf(&sb, "export type %sFieldCode = keyof %sRecord\n", name, name)
+
+	return sb.String()
+}
+
+func writeFieldTypes(sb *strings.Builder, fields map[string]kintone.FieldProperty, indent string) {
+	codes := make([]string, 0, len(fields))
+	for code := range fields {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		field := fields[code]
+		value, ok := fieldValueType(field, indent)
+		if !ok {
+			continue
+		}
+		if field.Label != "" && field.Label != code {
+			fmt.Fprintf(sb, "%s/** %s */\n", indent, strings.ReplaceAll(field.Label, "*/", "* /"))
+		}
+		fmt.Fprintf(sb, "%s%s: { type: '%s'; value: %s }\n", indent, tsPropertyName(code), field.Type, value)
+	}
+}
+
+// fieldValueType はフィールドの value の型を返す
+// レコードに値を持たないフィールド（GROUP・REFERENCE_TABLE など）は false を返す
+func fieldValueType(field kintone.FieldProperty, indent string) (string, bool) {
+	switch field.Type {
+	case "SINGLE_LINE_TEXT", "MULTI_LINE_TEXT", "RICH_TEXT", "NUMBER", "CALC", "LINK",
+		"DATE", "TIME", "DATETIME", "RECORD_NUMBER", "CREATED_TIME", "UPDATED_TIME", "STATUS":
+		return "string", true
+	case "RADIO_BUTTON":
+		return optionUnion(field.Options, false), true
+	case "DROP_DOWN":
+		return optionUnion(field.Options, true), true
+	case "CHECK_BOX", "MULTI_SELECT":
+		return arrayOf(optionUnion(field.Options, false)), true
+	case "CATEGORY":
+		return "string[]", true
+	case "USER_SELECT", "ORGANIZATION_SELECT", "GROUP_SELECT", "STATUS_ASSIGNEE":
+		return userEntityType + "[]", true
+	case "CREATOR", "MODIFIER":
+		return userEntityType, true
+	case "FILE":
+		return "{ contentType: string; fileKey: string; name: string; size: string }[]", true
+	case "SUBTABLE":
+		var sb strings.Builder
+		sb.WriteString("{\n")
+		fmt.Fprintf(&sb, "%s  id: string\n", indent)
+		fmt.Fprintf(&sb, "%s  value: {\n", indent)
+		writeFieldTypes(&sb, field.Fields, indent+"    ")
+		fmt.Fprintf(&sb, "%s  }\n", indent)
+		fmt.Fprintf(&sb, "%s}[]", indent)
+		return sb.String(), true
+	default:
+		return "", false
+	}
+}
+
+// optionUnion は選択肢の表示順に文字列リテラルの union 型を作る
+// 選択肢が取得できない場合は string
+func optionUnion(options map[string]kintone.FieldOption, allowEmpty bool) string {
+	if len(options) == 0 {
+		return "string"
+	}
+
+	labels := make([]string, 0, len(options))
+	for label := range options {
+		labels = append(labels, label)
+	}
+	sort.SliceStable(labels, func(i, j int) bool {
+		a, _ := strconv.Atoi(options[labels[i]].Index)
+		b, _ := strconv.Atoi(options[labels[j]].Index)
+		if a != b {
+			return a < b
+		}
+		return labels[i] < labels[j]
+	})
+
+	parts := make([]string, 0, len(labels)+1)
+	for _, label := range labels {
+		parts = append(parts, tsString(label))
+	}
+	if allowEmpty {
+		parts = append(parts, "''")
+	}
+	return strings.Join(parts, " | ")
+}
+
+func arrayOf(t string) string {
+	if strings.Contains(t, "|") {
+		return "(" + t + ")[]"
+	}
+	return t + "[]"
+}
+
+// tsPropertyName は ASCII の識別子以外のフィールドコードをクォートする
+// （・ など識別子に使えない文字を含む場合があるため、日本語のコードもクォートする）
+func tsPropertyName(code string) string {
+	if code == "" {
+		return "''"
+	}
+	for i, r := range code {
+		isLetter := r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		isDigit := r >= '0' && r <= '9'
+		if !isLetter && (i == 0 || !isDigit) {
+			return tsString(code)
+		}
+	}
+	return code
+}
+
+// tsString はシングルクォートの TypeScript 文字列リテラルを返す
+func tsString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`).Replace(s) + "'"
+}
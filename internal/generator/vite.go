@@ -1,14 +1,35 @@
 package generator
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/kintone/kpdev/internal/config"
 	"github.com/kintone/kpdev/internal/prompt"
 )
 
+// ViteConfigSchemaVersion は生成する vite.config.ts のバージョン
+// ミドルウェアの出力形式など、kpdev 本体と連携する部分を変更したら上げる
+const ViteConfigSchemaVersion = 14
+
+// viteConfigSchemaMarker は vite.config.ts の先頭に埋め込むバージョン表記
+func viteConfigSchemaMarker() string {
+	return fmt.Sprintf("// kpdev vite config schema: %d", ViteConfigSchemaVersion)
+}
+
+// IsViteConfigOutdated は既存の vite.config.ts が更新対象かどうかを返す
+func IsViteConfigOutdated(content string) bool {
+	// 現在のスキーマ（生成するファイルはコメントに handleHotUpdate を含むため先に判定する）
+	if strings.Contains(content, viteConfigSchemaMarker()) {
+		return false
+	}
+	// Vite 7 以前の handleHotUpdate 版、またはスキーマが古いもの
+	return true
+}
+
 func GenerateViteConfig(projectDir string, framework prompt.Framework, language prompt.Language) error {
 	configDir := config.GetConfigDir(projectDir)
 	if err := os.MkdirAll(configDir, 0755); err != nil {
@@ -18,7 +39,32 @@ func GenerateViteConfig(projectDir string, framework prompt.Framework, language
 	content := generateViteConfigContent(framework, language)
 	configPath := filepath.Join(configDir, "vite.config.ts")
 
-	return os.WriteFile(configPath, []byte(content), 0644)
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		return err
+	}
+
+	// vite.config.ts が提供する仮想モジュールの型（TypeScript の場合のみ）
+	if language == prompt.LanguageTypeScript {
+		return generateAssetsTypes(projectDir)
+	}
+	return nil
+}
+
+// assetsTypesFile は virtual:kpdev-assets の型定義
+const assetsTypesFile = "kpdev-assets.d.ts"
+
+func generateAssetsTypes(projectDir string) error {
+	dir := filepath.Join(projectDir, filepath.FromSlash(TypesDir))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	content := `// kpdev が生成（vite.config.ts の virtual:kpdev-assets の型）
+declare module 'virtual:kpdev-assets' {
+  /** assets/ からのパスを URL に変換する（開発時は開発サーバー、ビルド後はプラグインの assets/） */
+  export function assetUrl(path: string): string
+}
+`
+	return os.WriteFile(filepath.Join(dir, assetsTypesFile), []byte(content), 0644)
 }
 
 func generateViteConfigContent(framework prompt.Framework, language prompt.Language) string {
@@ -46,15 +92,16 @@ func generateViteConfigContent(framework prompt.Framework, language prompt.Langu
 	var plugins string
 	if pluginUse != "" {
 		plugins = fmt.Sprintf(`
-  plugins: [%s, kpdevMiddleware()],`, pluginUse)
+  plugins: [%s, kpdevMiddleware(), kpdevAssets(false)],`, pluginUse)
 	} else {
 		plugins = `
-  plugins: [kpdevMiddleware()],`
+  plugins: [kpdevMiddleware(), kpdevAssets(false)],`
 	}
 
-	return fmt.Sprintf(`import { defineConfig } from 'vite'
+	return viteConfigSchemaMarker() + "\n" + fmt.Sprintf(`import { defineConfig } from 'vite'
 import path from 'path'
 import fs from 'fs'
+import os from 'os'
 import { build } from 'vite'
 %s
 
@@ -63,38 +110,359 @@ const certDir = path.resolve(__dirname, 'certs')
 const keyPath = path.join(certDir, 'localhost-key.pem')
 const certPath = path.join(certDir, 'localhost.pem')
 
-// loader.meta.json からドメインを取得
-function getKintoneDomain(): string {
+// loader.meta.json を読み込む
+function readLoaderMeta(): any {
   try {
     const metaPath = path.resolve(__dirname, 'managed/loader.meta.json')
-    const meta = JSON.parse(fs.readFileSync(metaPath, 'utf-8'))
-    return meta.kintone?.domain || ''
+    return JSON.parse(fs.readFileSync(metaPath, 'utf-8'))
+  } catch {
+    return {}
+  }
+}
+
+// バンドルするエントリー（kpdev が .kpdev/config.json の dev.entry から KPDEV_ENTRIES で渡す）
+// キーはバンドル名（main / desktop / mobile / config）、値はプロジェクトルートからの / 始まりのパス
+function readEntries(): Record<string, string> {
+  try {
+    if (process.env.KPDEV_ENTRIES) {
+      return JSON.parse(process.env.KPDEV_ENTRIES)
+    }
   } catch {
-    return ''
+    // 解析できなければ既定のエントリーを使う
+  }
+  return { main: '/src/main/main%s', config: '/src/config/main%s' }
+}
+const entries = readEntries()
+
+// バンドル名からエントリーの絶対パスを返す
+function entryPath(name: string): string {
+  return path.resolve(__dirname, '..' + entries[name])
+}
+
+// 配信するファイル名（desktop.js など）から実際のバンドル名を返す
+// desktop / mobile に個別のエントリーがなければ main を共有する
+function resolveBundle(name: string): string | null {
+  if (entries[name]) {
+    return name
+  }
+  if (name === 'desktop' || name === 'mobile') {
+    return entries.main ? 'main' : null
+  }
+  if (name === 'main') {
+    return entries.desktop ? 'desktop' : entries.mobile ? 'mobile' : null
+  }
+  return null
+}
+
+// loader.meta.json からドメインを取得
+function getKintoneDomain(): string {
+  return readLoaderMeta().kintone?.domain || ''
+}
+
+// loader.meta.json から開発用プラグインIDを取得
+function getDevPluginId(): string {
+  return readLoaderMeta().pluginIds?.dev || ''
+}
+
+%s
+
+%s
+
+// --forward-console 指定時にバンドル先頭へ付与する console ラッパー
+// ローダーの eval スコープ内だけで console を差し替える（行番号がずれないよう1行にする）
+const CONSOLE_FORWARD_PRELUDE = 'var console = (function (c) { var w = Object.create(c); ["log", "info", "warn", "error", "debug"].forEach(function (level) { w[level] = function () { c[level].apply(c, arguments); try { var args = Array.prototype.map.call(arguments, function (a) { if (a instanceof Error) return a.stack || String(a); if (typeof a === "string") return a; try { return JSON.stringify(a) } catch (e) { return String(a) } }); window.__kpdev && window.__kpdev.report("console." + level, args.join(" "), new Error().stack, 1) } catch (e) {} } }); return w })(window.console);'
+
+// 配信中バンドルの source map（エラー位置を元ソースに変換するために保持）
+const sourceMaps = new Map<string, { map: any; offset: number }>()
+
+// source map の mappings（Base64 VLQ）をデコード
+const VLQ_CHARS = 'ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/'
+function decodeVLQ(segment: string): number[] {
+  const values: number[] = []
+  let value = 0
+  let shift = 0
+  for (const ch of segment) {
+    const digit = VLQ_CHARS.indexOf(ch)
+    value += (digit & 31) << shift
+    if (digit & 32) {
+      shift += 5
+    } else {
+      values.push(value & 1 ? -(value >>> 1) : value >>> 1)
+      value = 0
+      shift = 0
+    }
+  }
+  return values
+}
+
+// バンドル上の位置（1始まり）を元ソースの位置に変換
+function originalPosition(file: string, line: number, column: number): string | null {
+  const entry = sourceMaps.get(file)
+  if (!entry || !entry.map || !entry.map.mappings) {
+    return null
+  }
+  // console 転送用のラッパーは1行目の先頭に付与しているのでその分を戻す
+  if (line === 1) {
+    column -= entry.offset
+  }
+  const lines = entry.map.mappings.split(';')
+  let src = 0
+  let srcLine = 0
+  let srcCol = 0
+  let found: string | null = null
+  for (let i = 0; i < lines.length && i < line; i++) {
+    let genCol = 0
+    for (const seg of lines[i].split(',')) {
+      if (!seg) {
+        continue
+      }
+      const v = decodeVLQ(seg)
+      genCol += v[0]
+      if (v.length >= 4) {
+        src += v[1]
+        srcLine += v[2]
+        srcCol += v[3]
+        if (i === line - 1 && genCol <= column - 1) {
+          found = entry.map.sources[src] + ':' + (srcLine + 1) + ':' + (srcCol + 1)
+        }
+      }
+    }
+  }
+  return found
+}
+
+// スタックトレースから Dev server 上の位置を取り出し、元ソースの位置（src/main/App.tsx:10:5 など）で返す
+function findReportLocation(stack: string, skip: number): string {
+  const re = /https?:\/\/[^\/\s)]+\/([\w.-]+\.js):(\d+):(\d+)/g
+  const frames: string[] = []
+  let m
+  while ((m = re.exec(stack)) !== null) {
+    const bundle = resolveBundle(m[1].replace(/\.js$/, ''))
+    const original = originalPosition(bundle ? bundle + '.js' : m[1], Number(m[2]), Number(m[3]))
+    frames.push(original || m[1] + ':' + m[2] + ':' + m[3])
+  }
+  return frames[skip] || frames[0] || '-'
+}
+
+// ブラウザから転送されたエラーを1行で出力（kpdev dev が解析して表示する）
+function printBrowserReport(report: any) {
+  const level = String(report.level || 'uncaught')
+  const message = String(report.message || '').split('\n')[0]
+  const loc = findReportLocation(String(report.stack || ''), Number(report.skip) || 0)
+  const line = '[kpdev:browser] ' + level + ' ' + loc + ' ' + message
+  if (level === 'uncaught' || level === 'unhandledrejection' || level === 'console.error') {
+    console.error(line)
+  } else {
+    console.log(line)
+  }
+}
+
+// ビルドエラーをローダーが表示できる形式に変換
+function buildErrorPayload(e: any) {
+  const strip = (text: any) => String(text || '').replace(/\u001b\[[0-9;]*m/g, '')
+  return {
+    message: strip(e && e.message ? e.message : e),
+    id: e && e.id ? String(e.id) : '',
+    loc: e && e.loc ? { file: String(e.loc.file || e.id || ''), line: e.loc.line, column: e.loc.column } : null,
+    frame: strip(e && e.frame),
+  }
+}
+
+// エントリごとに watch モードの Vite ビルドを常駐させ、最新の成果物を保持する
+// 変更があったエントリだけが再ビルドされ、完了までは前回の成果物を配信する
+function createEntryBuilder(entry: string, onBuilt: (entry: string) => void) {
+  const state = {
+    code: '',
+    error: null as any,
+    watcher: null as any,
+    firstBuild: null as Promise<void> | null,
+  }
+  let resolveFirstBuild = () => {}
+  state.firstBuild = new Promise<void>((resolve) => { resolveFirstBuild = resolve })
+
+  // 出力をファイルに書き出さずメモリに取り込む
+  let latest: { code: string; map: any } | null = null
+  const capture = {
+    name: 'kpdev-capture',
+    generateBundle(_options: any, bundle: any) {
+      for (const name of Object.keys(bundle)) {
+        const chunk = bundle[name]
+        if (chunk.type === 'chunk' && chunk.isEntry) {
+          latest = { code: chunk.code, map: chunk.map }
+        }
+        delete bundle[name]
+      }
+    },
+  }
+
+  const apply = (output: { code: string; map: any }) => {
+    let code = output.code
+    if (output.map && code.indexOf('//# sourceMappingURL=') === -1) {
+      code += '\n//# sourceMappingURL=' + output.map.toUrl()
+    }
+    let offset = 0
+    if (process.env.KPDEV_FORWARD_CONSOLE === '1') {
+      code = CONSOLE_FORWARD_PRELUDE + code
+      offset = CONSOLE_FORWARD_PRELUDE.length
+    }
+    sourceMaps.set(entry + '.js', { map: output.map, offset })
+    state.code = code
+  }
+
+  const start = async () => {
+    try {
+      state.watcher = await build({
+        configFile: false,
+        root: path.resolve(__dirname, '..'),
+        logLevel: 'silent',
+        clearScreen: false,
+        plugins: [%s, kpdevAssets(true), capture].filter(Boolean),
+        build: {
+          outDir: path.join(os.tmpdir(), 'kpdev-dev-' + process.pid, entry),
+          emptyOutDir: false,
+          copyPublicDir: false,
+          minify: false,
+          // eval で読み込むため source map はインラインで埋め込む
+          sourcemap: 'inline',
+          watch: {},
+          rollupOptions: {
+            input: entryPath(entry),
+            output: {
+              format: 'iife',
+              entryFileNames: '[name].js',
+              // DevTools で https://localhost:3000/src/... として解決されるようにする
+              sourcemapPathTransform: (relativePath: string) => relativePath.replace(/^(\.\.\/)+/, ''),
+            },
+          },
+        },
+      })
+    } catch (e) {
+      state.error = e
+      console.error('IIFE build error:', e)
+      resolveFirstBuild()
+      return
+    }
+
+    state.watcher.on('event', (event: any) => {
+      if (event.code === 'BUNDLE_START') {
+        latest = null
+      }
+      if (event.code === 'BUNDLE_END') {
+        if (latest) {
+          apply(latest)
+        }
+        state.error = null
+        // kpdev dev のダッシュボードがビルド時間を表示するためのログ
+        console.log('[kpdev] build ' + entry + ' ' + event.duration + 'ms')
+        resolveFirstBuild()
+        onBuilt(entry)
+      }
+      if (event.code === 'ERROR') {
+        state.error = event.error
+        console.error('IIFE build error:', event.error)
+        resolveFirstBuild()
+        onBuilt(entry)
+      }
+      if (event.result && typeof event.result.close === 'function') {
+        event.result.close()
+      }
+    })
+  }
+
+  return {
+    state,
+    start,
+    close: () => {
+      if (state.watcher) {
+        state.watcher.close()
+      }
+    },
+  }
+}
+
+// アセット（プロジェクトルートの assets/、なければ public/）
+// kpdev build がプラグインの assets/ に同じパスでコピーする
+const assetsDir = ['assets', 'public']
+  .map((dir) => path.resolve(__dirname, '..', dir))
+  .find((dir) => fs.existsSync(dir)) || path.resolve(__dirname, '../assets')
+
+const ASSET_CONTENT_TYPES: Record<string, string> = {
+  '.png': 'image/png',
+  '.jpg': 'image/jpeg',
+  '.jpeg': 'image/jpeg',
+  '.gif': 'image/gif',
+  '.svg': 'image/svg+xml',
+  '.webp': 'image/webp',
+  '.ico': 'image/x-icon',
+  '.json': 'application/json',
+  '.woff': 'font/woff',
+  '.woff2': 'font/woff2',
+  '.ttf': 'font/ttf',
+  '.otf': 'font/otf',
+  '.css': 'text/css',
+  '.txt': 'text/plain',
+}
+
+// virtual:kpdev-assets（アセットの URL を返す assetUrl() を提供する仮想モジュール）
+// 開発時は開発サーバーの /assets/、ビルド時は KPDEV_ASSETS_BASE（build.assetsBaseUrl）を参照する
+// kintone はプラグインZIP内のファイルを URL で配信しないため、ビルド後のアセットはプラグインの外に置く
+function kpdevAssets(dev: boolean) {
+  const id = 'virtual:kpdev-assets'
+  return {
+    name: 'kpdev-assets',
+    resolveId(source: string) {
+      return source === id ? '\0' + id : null
+    },
+    load(this: any, resolved: string) {
+      if (resolved !== '\0' + id) {
+        return null
+      }
+      let base: string
+      if (dev) {
+        base = (readLoaderMeta().dev?.origin || 'https://localhost:3000') + '/assets/'
+      } else {
+        if (!process.env.KPDEV_ASSETS_BASE) {
+          this.error('virtual:kpdev-assets を使うには .kpdev/config.json の build.assetsBaseUrl を指定してください')
+        }
+        base = (process.env.KPDEV_ASSETS_BASE || '').replace(/\/*$/, '/')
+      }
+      return 'const base = ' + JSON.stringify(base) + '\n' +
+        "export function assetUrl(path) { return base + String(path).replace(/^\\/+/, '') }\n"
+    },
   }
 }
 
 // kpdev 開発サーバー用ミドルウェア
 function kpdevMiddleware() {
-  const cache = new Map<string, { code: string; time: number }>()
-  const CACHE_TTL = 1000 // 1秒キャッシュ
-
   return {
     name: 'kpdev-middleware',
     configureServer(server: any) {
+      // 初回ビルド後は、再ビルドが完了したタイミングでリロードする
+      const built = new Set<string>()
+      const builders = new Map<string, ReturnType<typeof createEntryBuilder>>()
+      for (const entry of Object.keys(entries)) {
+        const builder = createEntryBuilder(entry, (name) => {
+          if (built.has(name)) {
+            server.ws.send({ type: 'full-reload' })
+          }
+          built.add(name)
+        })
+        builders.set(entry, builder)
+        builder.start()
+      }
+      server.httpServer?.once('close', () => {
+        builders.forEach((builder) => builder.close())
+      })
+
       // Vite 7 対応: handleHotUpdate の代わりに watcher を使用
       server.watcher.on('change', (file: string) => {
         // src/config/index.html は kpdev が監視して再デプロイ後に HMR 発火するので無視
         if (file.includes('src/config') && file.endsWith('.html')) {
           console.log('[kpdev] HTML change detected, waiting for redeploy...')
-          return
         }
-        cache.clear()
-        server.ws.send({ type: 'full-reload' })
       })
       server.middlewares.use(async (req: any, res: any, next: any) => {
         const url = req.url?.split('?')[0]
-
         // ルートアクセス時は kintone へリダイレクト
         if (url === '/' || url === '/index.html') {
           const domain = getKintoneDomain()
@@ -150,6 +518,65 @@ function kpdevMiddleware() {
           return
         }
 
+        // ブラウザからのエラー転送
+        if (url === '/__kpdev/report' && req.method === 'POST') {
+          try {
+            printBrowserReport(JSON.parse(await readRequestBody(req)))
+          } catch {
+            // 不正な形式は無視
+          }
+          res.statusCode = 204
+          res.setHeader('Access-Control-Allow-Origin', '*')
+          res.end()
+          return
+        }
+
+        // 設定画面プレビュー
+        if (url === '/__kpdev/config-preview') {
+          res.setHeader('Content-Type', 'text/html; charset=utf-8')
+          res.end(configPreviewHTML())
+          return
+        }
+
+        // kintone API スタブ
+        if (url === '/__kpdev/kintone-stub.js') {
+          res.setHeader('Content-Type', 'application/javascript; charset=utf-8')
+          res.setHeader('Cache-Control', 'no-store')
+          res.end(kintoneStubScript(/[?&]sandbox=1/.test(req.url || '')))
+          return
+        }
+
+        // sandbox（PC用のバンドルを疑似 kintone 画面で実行）
+        if (url === '/__kpdev/sandbox') {
+          res.setHeader('Content-Type', 'text/html; charset=utf-8')
+          res.end(sandboxHTML())
+          return
+        }
+
+        // プレビューの設定値（GET: 取得 / POST: required_params を検証して保存）
+        if (url === '/__kpdev/preview/config') {
+          if (req.method !== 'POST') {
+            sendJSON(res, 200, readPreviewConfig())
+            return
+          }
+          let config: any
+          try {
+            config = JSON.parse(await readRequestBody(req))
+          } catch {
+            sendJSON(res, 400, { message: '設定値のJSONが不正です' })
+            return
+          }
+          const missing = getRequiredParams().filter((key) => typeof config[key] !== 'string' || config[key] === '')
+          if (missing.length > 0) {
+            sendJSON(res, 400, { message: '必須パラメータが設定されていません: ' + missing.join(', '), missing })
+            return
+          }
+          writePreviewConfig(config)
+          console.log('[kpdev] preview config saved: .kpdev/preview/config.json')
+          sendJSON(res, 200, { ok: true })
+          return
+        }
+
         // /config.html は src/config/index.html を配信
         if (url === '/config.html') {
           try {
@@ -164,53 +591,43 @@ function kpdevMiddleware() {
           }
         }
 
-        // /main.js, /config.js は IIFE ビルドして配信
-        if (url === '/main.js' || url === '/config.js') {
-          const entry = url === '/main.js' ? 'main' : 'config'
-          const entryPath = path.resolve(__dirname, '../src/' + entry + '/main%s')
-
+        // /assets/ 以下は assets/ のファイルを配信（開発時の assetUrl() の参照先）
+        if (url && url.startsWith('/assets/')) {
           try {
-            // キャッシュチェック
-            const cached = cache.get(entry)
-            const now = Date.now()
-            if (cached && (now - cached.time) < CACHE_TTL) {
-              res.setHeader('Content-Type', 'application/javascript')
+            const file = path.resolve(assetsDir, decodeURIComponent(url.slice('/assets/'.length)))
+            if (file.startsWith(assetsDir + path.sep) && fs.statSync(file).isFile()) {
               res.setHeader('Access-Control-Allow-Origin', '*')
-              res.end(cached.code)
+              res.setHeader('Content-Type', ASSET_CONTENT_TYPES[path.extname(file).toLowerCase()] || 'application/octet-stream')
+              res.setHeader('Cache-Control', 'no-store')
+              fs.createReadStream(file).pipe(res)
               return
             }
+          } catch {
+            // 存在しないファイル・不正なパスは 404
+          }
+          res.statusCode = 404
+          res.setHeader('Access-Control-Allow-Origin', '*')
+          res.end()
+          return
+        }
 
-            // Vite でオンデマンドビルド
-            const result = await build({
-              configFile: false,
-              root: path.resolve(__dirname, '..'),
-              logLevel: 'silent',
-              plugins: [%s].filter(Boolean),
-              build: {
-                write: false,
-                minify: false,
-                rollupOptions: {
-                  input: entryPath,
-                  output: {
-                    format: 'iife',
-                    entryFileNames: '[name].js',
-                  },
-                },
-              },
-            })
-
-            const output = (result as any).output || (result as any)[0]?.output
-            if (output && output[0]) {
-              const code = output[0].code
-              cache.set(entry, { code, time: now })
-              res.setHeader('Content-Type', 'application/javascript')
-              res.setHeader('Access-Control-Allow-Origin', '*')
-              res.end(code)
-              return
-            }
-          } catch (e) {
-            console.error('IIFE build error:', e)
+        // /main.js, /desktop.js, /mobile.js, /config.js は常駐ビルドの最新の成果物を配信
+        const served = /^\/(main|desktop|mobile|config)\.js$/.exec(url)
+        const bundle = served ? resolveBundle(served[1]) : null
+        if (bundle) {
+          const builder = builders.get(bundle)!
+          await builder.state.firstBuild
+          res.setHeader('Access-Control-Allow-Origin', '*')
+          if (builder.state.error) {
+            // ローダーが kintone 上にエラー内容を表示する
+            res.statusCode = 500
+            res.setHeader('Content-Type', 'application/json')
+            res.end(JSON.stringify(buildErrorPayload(builder.state.error)))
+            return
           }
+          res.setHeader('Content-Type', 'application/javascript')
+          res.end(builder.state.code)
+          return
         }
         next()
       })
@@ -218,8 +635,44 @@ function kpdevMiddleware() {
   }
 }
 
+// kpdev build がバンドルごとに VITE_BUILD_ENTRY で指定する
+const buildEntry = process.env.VITE_BUILD_ENTRY || 'main'
+
+// バンドルせず js.cybozu.com の UMD 版を読み込むモジュール → グローバル変数
+// （kpdev build が .kpdev/config.json の build.externals から KPDEV_EXTERNALS で渡す）
+function readExternals(): Record<string, string> {
+  try {
+    if (process.env.KPDEV_EXTERNALS) {
+      return JSON.parse(process.env.KPDEV_EXTERNALS)
+    }
+  } catch {
+    // 解析できなければすべてバンドルする
+  }
+  return {}
+}
+const externals = readExternals()
+
+// ビルド時に置き換える定数（キー → JSON の値）
+// （kpdev build がビルドプロファイルの define から KPDEV_DEFINE で渡す）
+function readDefines(): Record<string, string> {
+  try {
+    if (process.env.KPDEV_DEFINE) {
+      return JSON.parse(process.env.KPDEV_DEFINE)
+    }
+  } catch {
+    // 解析できなければ置き換えない
+  }
+  return {}
+}
+
+// ソースマップ（kpdev build がビルドプロファイルの sourcemap から KPDEV_SOURCEMAP で渡す）
+const sourcemap = process.env.KPDEV_SOURCEMAP === 'inline' || process.env.KPDEV_SOURCEMAP === 'hidden'
+  ? (process.env.KPDEV_SOURCEMAP as 'inline' | 'hidden')
+  : false
+
 export default defineConfig({%s
   root: path.resolve(__dirname, '..'),
+  define: readDefines(),
   server: {
     port: 3000,
     https: fs.existsSync(keyPath) && fs.existsSync(certPath)
@@ -237,25 +690,62 @@ export default defineConfig({%s
     },
   },
   build: {
-    outDir: 'dist',
-    emptyOutDir: process.env.VITE_BUILD_ENTRY !== 'config',
+    // kpdev build はバンドルごとの出力先（dist/.vite/{bundle}）を KPDEV_OUT_DIR で指定して並列にビルドする
+    outDir: process.env.KPDEV_OUT_DIR || 'dist',
+    // dist/ は kpdev build がビルド前に削除する
+    emptyOutDir: false,
     rollupOptions: {
-      input: process.env.VITE_BUILD_ENTRY === 'config'
-        ? { config: path.resolve(__dirname, '../src/config/main%s') }
-        : { main: path.resolve(__dirname, '../src/main/main%s') },
+      input: { [buildEntry]: entryPath(buildEntry) },
+      external: Object.keys(externals),
       output: {
         format: 'iife',
+        globals: externals,
         entryFileNames: '[name].js',
-        assetFileNames: process.env.VITE_BUILD_ENTRY === 'config' ? 'config.[ext]' : 'main.[ext]',
+        assetFileNames: buildEntry + '.[ext]',
       },
     },
     cssCodeSplit: false,
+    // assets/（public/）は kpdev build がプラグインにコピーする
+    copyPublicDir: false,
     minify: 'esbuild',
+    sourcemap,
   },
   esbuild: {
-    drop: ['console', 'debugger'],
+    // ビルドプロファイルで console を残す場合は KPDEV_REMOVE_CONSOLE=0 が渡される
+    drop: process.env.KPDEV_REMOVE_CONSOLE === '0' ? [] : ['console', 'debugger'],
   },
 })
-`, pluginImport, ext, pluginUse, plugins, ext, ext)
+`, pluginImport, ext, ext, viteConfigPreviewHelpers, viteConfigSandboxHelpers, pluginUse, plugins)
 }
 
+// ViteEntriesEnv は vite.config.ts にバンドルするエントリーを渡す環境変数を返す
+func ViteEntriesEnv(cfg *config.Config) string {
+	data, _ := json.Marshal(cfg.Bundles())
+	return "KPDEV_ENTRIES=" + string(data)
+}
+
+// ViteExternalsEnv は vite.config.ts に外部化するモジュールとグローバル変数を渡す環境変数を返す
+func ViteExternalsEnv(globals map[string]string) string {
+	data, _ := json.Marshal(globals)
+	return "KPDEV_EXTERNALS=" + string(data)
+}
+
+// ViteAssetsEnv は vite.config.ts に build.assetsBaseUrl（ビルド後のアセットを配信する URL）を渡す環境変数を返す
+// アセットを使う場合は必須で、バンドルの URL から求めることはない（空なら vite.config.ts がエラーにする）
+func ViteAssetsEnv(baseURL string) string {
+	return "KPDEV_ASSETS_BASE=" + baseURL
+}
+
+// ViteProfileEnv は vite.config.ts にビルドプロファイルの設定を渡す環境変数を返す
+func ViteProfileEnv(removeConsole bool, sourcemap string, defines map[string]string) []string {
+	remove := "1"
+	if !removeConsole {
+		remove = "0"
+	}
+	data, _ := json.Marshal(defines)
+	return []string{
+		"KPDEV_REMOVE_CONSOLE=" + remove,
+		"KPDEV_SOURCEMAP=" + sourcemap,
+		"KPDEV_DEFINE=" + string(data),
+	}
+}
@@ -0,0 +1,170 @@
+package generator
+
+// viteConfigPreviewHelpers は vite.config.ts に埋め込む設定画面プレビュー用のヘルパー
+// getConfig/setConfig の値は .kpdev/preview/config.json に保存する
+const viteConfigPreviewHelpers = `// 設定画面プレビューの保存先
+const previewConfigPath = path.resolve(__dirname, 'preview/config.json')
+
+function readPreviewConfig(): Record<string, string> {
+  try {
+    return JSON.parse(fs.readFileSync(previewConfigPath, 'utf-8'))
+  } catch {
+    return {}
+  }
+}
+
+function writePreviewConfig(config: Record<string, string>) {
+  fs.mkdirSync(path.dirname(previewConfigPath), { recursive: true })
+  fs.writeFileSync(previewConfigPath, JSON.stringify(config, null, 2) + '\n')
+}
+
+// manifest.json の required_params（config 内を優先、トップレベルもフォールバック）
+function getRequiredParams(): string[] {
+  try {
+    const manifest = JSON.parse(fs.readFileSync(path.resolve(__dirname, 'manifest.json'), 'utf-8'))
+    const params = (manifest.config && manifest.config.required_params) || manifest.required_params || []
+    return Array.isArray(params) ? params.map(String) : []
+  } catch {
+    return []
+  }
+}
+
+function escapeHTML(text: string): string {
+  return text.replace(/[&<>"']/g, (c) => '&#' + c.charCodeAt(0) + ';')
+}
+
+function readRequestBody(req: any): Promise<string> {
+  return new Promise((resolve, reject) => {
+    let body = ''
+    req.on('data', (chunk: any) => { body += chunk })
+    req.on('end', () => resolve(body))
+    req.on('error', reject)
+  })
+}
+
+function sendJSON(res: any, status: number, data: any) {
+  res.statusCode = status
+  res.setHeader('Content-Type', 'application/json; charset=utf-8')
+  res.setHeader('Access-Control-Allow-Origin', '*')
+  res.end(JSON.stringify(data))
+}
+
+// ブラウザ側で実行する kintone API のスタブ（toString してページに埋め込む）
+function kintoneStub() {
+  const w = window as any
+  const data = w.__KPDEV_PREVIEW__ || {}
+  let config: Record<string, string> = data.config || {}
+
+  const notify = (message: string, isError: boolean) => {
+    let box = document.getElementById('kpdev-preview-notice')
+    if (!box) {
+      box = document.createElement('div')
+      box.id = 'kpdev-preview-notice'
+      document.body.appendChild(box)
+    }
+    box.className = isError ? 'kpdev-notice kpdev-notice-error' : 'kpdev-notice'
+    box.textContent = message
+  }
+
+  const kintone = w.kintone || {}
+  kintone.$PLUGIN_ID = data.pluginId || ''
+  kintone.plugin = kintone.plugin || {}
+  kintone.plugin.app = Object.assign(kintone.plugin.app || {}, {
+    getConfig: (pluginId: string) => {
+      if (pluginId !== kintone.$PLUGIN_ID) {
+        console.warn('[kpdev] getConfig: プラグインIDが一致しません: ' + pluginId)
+        return {}
+      }
+      return Object.assign({}, config)
+    },
+    setConfig: (newConfig: Record<string, string>, successCallback?: () => void) => {
+      const next = newConfig || {}
+      for (const key of Object.keys(next)) {
+        if (typeof next[key] !== 'string') {
+          throw new Error('setConfig: 値は文字列で指定してください (' + key + ')')
+        }
+      }
+      fetch('/__kpdev/preview/config', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(next),
+      })
+        .then((res) => res.json().catch(() => ({})).then((body: any) => ({ ok: res.ok, body })))
+        .then(({ ok, body }) => {
+          if (!ok) {
+            notify(body.message || '設定の保存に失敗しました', true)
+            return
+          }
+          config = Object.assign({}, next)
+          if (successCallback) {
+            notify('設定を保存しました', false)
+            successCallback()
+          } else {
+            notify('設定を保存しました（kintone ではプラグイン一覧に戻ります）', false)
+          }
+        })
+        .catch(() => notify('開発サーバーに接続できません', true))
+    },
+  })
+  w.kintone = kintone
+}
+
+// kintone-stub.js（プレビュー用データ + スタブ本体）
+// sandbox 指定時はアプリ画面の API スタブとフィクスチャも含める
+function kintoneStubScript(sandbox: boolean): string {
+  const data: any = {
+    pluginId: getDevPluginId(),
+    config: readPreviewConfig(),
+    requiredParams: getRequiredParams(),
+  }
+  let script = '(' + kintoneStub.toString() + ')();\n'
+  if (sandbox) {
+    const fixtures = readSandboxFixtures()
+    data.app = fixtures.app
+    data.records = fixtures.records
+    script += '(' + kintoneAppStub.toString() + ')();\n'
+  }
+  return 'window.__KPDEV_PREVIEW__ = ' + JSON.stringify(data) + ';\n' + script
+}
+
+const PREVIEW_CSS = [
+  'body { margin: 0; font-family: system-ui, -apple-system, sans-serif; background: #f7f9fa; color: #333; }',
+  '.kpdev-preview-header { display: flex; gap: 16px; align-items: center; padding: 10px 16px; background: #3498db; color: #fff; font-size: 13px; }',
+  '.kpdev-preview-header strong { font-size: 14px; }',
+  '.kpdev-preview-header code { background: rgba(255,255,255,0.2); padding: 1px 6px; border-radius: 3px; }',
+  '.kpdev-preview-body { margin: 24px; padding: 24px; background: #fff; border: 1px solid #e3e7e8; }',
+  '.kpdev-notice { position: fixed; right: 16px; bottom: 16px; padding: 10px 16px; border-radius: 4px; background: #2e7d32; color: #fff; font-size: 13px; }',
+  '.kpdev-notice-error { background: #c62828; }',
+].join('\n')
+
+// /__kpdev/config-preview（src/config/index.html + config エントリ）
+function configPreviewHTML(): string {
+  let body = '<div id="config-root"></div>'
+  try {
+    body = fs.readFileSync(path.resolve(__dirname, '../src/config/index.html'), 'utf-8')
+  } catch {
+    // index.html がない場合はデフォルト
+  }
+  const required = getRequiredParams()
+  return '<!DOCTYPE html>' +
+'<html lang="ja">' +
+'<head>' +
+'  <meta charset="UTF-8">' +
+'  <title>kpdev - 設定画面プレビュー</title>' +
+'  <style>' + PREVIEW_CSS + '</style>' +
+'  <script type="module" src="/@vite/client"></script>' +
+'  <script src="/__kpdev/kintone-stub.js"></script>' +
+'</head>' +
+'<body>' +
+'  <div class="kpdev-preview-header">' +
+'    <strong>設定画面プレビュー</strong>' +
+'    <span>Plugin ID: <code>' + escapeHTML(getDevPluginId()) + '</code></span>' +
+'    <span>保存先: <code>.kpdev/preview/config.json</code></span>' +
+(required.length > 0 ? '    <span>必須パラメータ: <code>' + escapeHTML(required.join(', ')) + '</code></span>' : '') +
+'  </div>' +
+'  <div class="kpdev-preview-body">' + body + '</div>' +
+'  <script src="/config.js"></script>' +
+'</body>' +
+'</html>'
+}
+`
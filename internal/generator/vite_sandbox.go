@@ -0,0 +1,549 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/kintone/kpdev/internal/config"
+)
+
+// defaultSandboxFixtures は kpdev sandbox のフィクスチャの初期値
+const defaultSandboxFixtures = `{
+  "app": { "id": 1, "name": "サンプルアプリ" },
+  "records": [
+    {
+      "$id": { "type": "__ID__", "value": "1" },
+      "タイトル": { "type": "SINGLE_LINE_TEXT", "value": "最初のレコード" },
+      "数値": { "type": "NUMBER", "value": "10" },
+      "ステータス": { "type": "DROP_DOWN", "value": "未着手" }
+    },
+    {
+      "$id": { "type": "__ID__", "value": "2" },
+      "タイトル": { "type": "SINGLE_LINE_TEXT", "value": "2件目のレコード" },
+      "数値": { "type": "NUMBER", "value": "25" },
+      "ステータス": { "type": "DROP_DOWN", "value": "完了" }
+    }
+  ]
+}
+`
+
+// EnsureSandboxFixtures は .kpdev/sandbox/records.json が無ければ初期値で作成する
+// 作成した場合は true を返す
+func EnsureSandboxFixtures(projectDir string) (bool, error) {
+	path := filepath.Join(config.GetConfigDir(projectDir), "sandbox", "records.json")
+	if _, err := os.Stat(path); err == nil {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(path, []byte(defaultSandboxFixtures), 0644); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// viteConfigSandboxHelpers は vite.config.ts に埋め込む kpdev sandbox 用のヘルパー
+// 一覧・詳細画面の疑似シェルと kintone アプリ画面 API のスタブを提供する
+const viteConfigSandboxHelpers = `// sandbox のフィクスチャ（.kpdev/sandbox/records.json）
+const sandboxRecordsPath = path.resolve(__dirname, 'sandbox/records.json')
+
+function readSandboxFixtures(): { app: any; records: any[] } {
+  try {
+    const data = JSON.parse(fs.readFileSync(sandboxRecordsPath, 'utf-8'))
+    return {
+      app: data.app || { id: 1, name: 'sandbox' },
+      records: Array.isArray(data.records) ? data.records : [],
+    }
+  } catch {
+    return { app: { id: 1, name: 'sandbox' }, records: [] }
+  }
+}
+
+// ブラウザ側で実行する kintone アプリ画面 API のスタブ（kintoneStub の後に実行する）
+function kintoneAppStub() {
+  const w = window as any
+  const data = w.__KPDEV_PREVIEW__ || {}
+  const kintone = w.kintone
+  const handlers: Record<string, any[]> = {}
+  const clone = (value: any) => JSON.parse(JSON.stringify(value))
+
+  const sandbox: any = {
+    app: data.app || { id: 1, name: 'sandbox' },
+    records: data.records || [],
+    current: null,
+    mobile: false,
+  }
+
+  const recordId = (record: any) => (record && record.$id ? Number(record.$id.value) : null)
+  const findRecord = (id: any) => sandbox.records.find((r: any) => String(recordId(r)) === String(id)) || null
+
+  // イベントを登録順に実行し、ハンドラが返したイベントオブジェクトを次に渡す
+  sandbox.fire = async (type: string, extra: any) => {
+    let event: any = Object.assign({ type: type, appId: sandbox.app.id }, extra || {})
+    const list = (handlers[type] || []).slice()
+    for (const handler of list) {
+      const result = await handler(event)
+      if (result && typeof result === 'object') {
+        event = result
+      }
+    }
+    return { event: event, handlerCount: list.length }
+  }
+
+  const ensureSpace = (id: string, parentId: string) => {
+    let el = document.getElementById(id)
+    if (!el) {
+      el = document.createElement('div')
+      el.id = id
+      el.className = 'kpdev-sb-space'
+      const parent = document.getElementById(parentId) || document.body
+      parent.appendChild(el)
+    }
+    return el
+  }
+
+  const fieldRow = (code: string): any => document.querySelector('[data-field-code="' + code + '"]')
+
+  const recordApi = () => ({
+    get: () => (sandbox.current ? { record: clone(sandbox.current) } : null),
+    set: (value: any) => {
+      if (sandbox.current && value && value.record) {
+        sandbox.current = clone(value.record)
+        if (sandbox.onChange) {
+          sandbox.onChange()
+        }
+      }
+    },
+    getId: () => recordId(sandbox.current),
+    getSpaceElement: (id: string) => ensureSpace('kpdev-space-' + id, 'kpdev-record-spaces'),
+    getFieldElement: (code: string) => {
+      const row = fieldRow(code)
+      return row ? row.querySelector('.kpdev-sb-value') : null
+    },
+    setFieldShown: (code: string, shown: boolean) => {
+      const row = fieldRow(code)
+      if (row) {
+        row.style.display = shown ? '' : 'none'
+      }
+    },
+    setGroupFieldOpen: () => {},
+  })
+
+  kintone.events = {
+    on: (type: any, handler: any) => {
+      (Array.isArray(type) ? type : [type]).forEach((t: string) => {
+        (handlers[t] = handlers[t] || []).push(handler)
+      })
+    },
+    off: (type?: any, handler?: any) => {
+      if (!type) {
+        Object.keys(handlers).forEach((t) => delete handlers[t])
+        return true
+      }
+      (Array.isArray(type) ? type : [type]).forEach((t: string) => {
+        handlers[t] = handler ? (handlers[t] || []).filter((h) => h !== handler) : []
+      })
+      return true
+    },
+  }
+
+  const appApi = {
+    getId: () => sandbox.app.id,
+    getLookupTargetAppId: () => null,
+    getRelatedRecordsTargetAppId: () => null,
+    getQuery: () => '',
+    getQueryCondition: () => '',
+    getHeaderMenuSpaceElement: () => document.getElementById('kpdev-header-menu-space'),
+    getHeaderSpaceElement: () => document.getElementById('kpdev-header-space'),
+    record: recordApi(),
+  }
+  kintone.app = Object.assign(kintone.app || {}, appApi)
+  kintone.mobile = {
+    app: {
+      getId: appApi.getId,
+      getQuery: appApi.getQuery,
+      getQueryCondition: appApi.getQueryCondition,
+      getHeaderSpaceElement: appApi.getHeaderSpaceElement,
+      record: recordApi(),
+    },
+  }
+  kintone.getLoginUser = () => ({ id: '1', code: 'kpdev', name: 'kpdev sandbox', email: '', language: 'ja', timezone: 'Asia/Tokyo' })
+  kintone.getUiVersion = () => 2
+  kintone.Promise = Promise
+
+  // kintone.api はフィクスチャのレコード取得のみ対応
+  const api = (pathOrUrl: string, method: string, params: any, success?: any, failure?: any) => {
+    const p = String(pathOrUrl).replace(/^https?:\/\/[^/]+/, '').replace(/\.json.*$/, '').replace(/^\/k\/(guest\/\d+\/)?v1/, '')
+    let result: Promise<any>
+    if (method === 'GET' && p === '/record') {
+      const record = findRecord(params && params.id)
+      result = record ? Promise.resolve({ record: clone(record) }) : Promise.reject({ code: 'GAIA_RE01', message: '指定したレコードが見つかりません。' })
+    } else if (method === 'GET' && p === '/records') {
+      result = Promise.resolve({ records: clone(sandbox.records), totalCount: String(sandbox.records.length) })
+    } else if (method === 'GET' && p === '/app') {
+      result = Promise.resolve({ appId: String(sandbox.app.id), name: sandbox.app.name })
+    } else {
+      result = Promise.reject({ code: 'KPDEV_SANDBOX', message: 'sandbox では ' + method + ' ' + p + ' は使用できません' })
+    }
+    if (success || failure) {
+      result.then(success, failure)
+      return undefined
+    }
+    return result
+  }
+  kintone.api = Object.assign(api, {
+    url: (p: string) => '/k/v1' + p.replace(/^\/k\/v1/, '') + '.json',
+    urlForGet: (p: string) => '/k/v1' + p.replace(/^\/k\/v1/, '') + '.json',
+  })
+  kintone.proxy = () => Promise.reject({ message: 'sandbox では kintone.proxy は使用できません' })
+
+  w.__kpdev = Object.assign(w.__kpdev || {}, { sandbox: sandbox })
+}
+
+// ブラウザ側で実行する sandbox の画面（一覧・詳細・編集・追加とイベントパネル）
+function sandboxUI() {
+  const w = window as any
+  const sandbox = w.__kpdev.sandbox
+  const $ = (id: string): any => document.getElementById(id)
+  const clone = (value: any) => JSON.parse(JSON.stringify(value))
+  let view = 'index'
+
+  const prefix = () => (sandbox.mobile ? 'mobile.' : '')
+  const recordId = (record: any) => (record && record.$id ? record.$id.value : '')
+  const fieldCodes = (record: any) => Object.keys(record || {}).filter((code) => code.charAt(0) !== '$')
+
+  const formatValue = (field: any): string => {
+    if (!field || field.value === null || field.value === undefined) {
+      return ''
+    }
+    const value = field.value
+    if (field.type === 'SUBTABLE') {
+      return value.length + ' 行'
+    }
+    if (field.type === 'FILE') {
+      return value.map((f: any) => f.name).join(', ')
+    }
+    if (Array.isArray(value)) {
+      return value.map((v: any) => (typeof v === 'object' ? v.name || v.code : v)).join(', ')
+    }
+    if (typeof value === 'object') {
+      return value.name || value.code || JSON.stringify(value)
+    }
+    return String(value)
+  }
+
+  const el = (tag: string, text?: string, className?: string) => {
+    const node = document.createElement(tag)
+    if (text !== undefined) {
+      node.textContent = text
+    }
+    if (className) {
+      node.className = className
+    }
+    return node
+  }
+
+  // イベントの発火結果をパネルに記録
+  const log = (type: string, outcome: any) => {
+    const item = el('details', undefined, 'kpdev-sb-log-item')
+    const error = outcome.event && outcome.event.error
+    const summary = el('summary', new Date().toLocaleTimeString() + ' ' + type + ' (' + outcome.handlerCount + ')')
+    if (error) {
+      summary.className = 'kpdev-sb-log-error'
+    }
+    item.appendChild(summary)
+    item.appendChild(el('pre', error ? 'error: ' + error : JSON.stringify(outcome.event, null, 2)))
+    $('kpdev-sb-log').prepend(item)
+  }
+
+  const fire = async (type: string, extra: any) => {
+    try {
+      const outcome = await sandbox.fire(type, extra)
+      log(type, outcome)
+      return outcome
+    } catch (e: any) {
+      log(type, { handlerCount: '!', event: { error: String(e && e.stack ? e.stack : e) } })
+      return null
+    }
+  }
+
+  const clearSpaces = () => {
+    $('kpdev-header-menu-space').innerHTML = ''
+    $('kpdev-header-space').innerHTML = ''
+    $('kpdev-record-spaces').innerHTML = ''
+  }
+
+  const showError = (message: string) => {
+    const box = el('div', message, 'kpdev-sb-error')
+    $('kpdev-sb-content').prepend(box)
+  }
+
+  const renderIndex = async () => {
+    view = 'index'
+    sandbox.current = null
+    clearSpaces()
+    const content = $('kpdev-sb-content')
+    content.innerHTML = ''
+    const codes = fieldCodes(sandbox.records[0]).slice(0, 6)
+    const table = el('table', undefined, 'kpdev-sb-table')
+    const head = el('tr')
+    head.appendChild(el('th', '$id'))
+    codes.forEach((code) => head.appendChild(el('th', code)))
+    table.appendChild(head)
+    sandbox.records.forEach((record: any) => {
+      const row = el('tr')
+      row.appendChild(el('td', recordId(record)))
+      codes.forEach((code) => row.appendChild(el('td', formatValue(record[code]))))
+      row.onclick = () => renderRecord('detail', record)
+      table.appendChild(row)
+    })
+    content.appendChild(table)
+    await fire(prefix() + 'app.record.index.show', {
+      viewType: 'list',
+      viewId: 1,
+      viewName: '（すべて）',
+      records: clone(sandbox.records),
+      offset: 0,
+      size: sandbox.records.length,
+      date: null,
+    })
+  }
+
+  const renderFields = (mode: string) => {
+    const form = $('kpdev-sb-form')
+    form.innerHTML = ''
+    fieldCodes(sandbox.current).forEach((code) => {
+      const field = sandbox.current[code]
+      const row = el('div', undefined, 'kpdev-sb-field')
+      row.setAttribute('data-field-code', code)
+      row.appendChild(el('label', code + ' (' + field.type + ')'))
+      const editable = mode !== 'detail' && (typeof field.value === 'string' || typeof field.value === 'number')
+      if (editable) {
+        const input: any = el('input', undefined, 'kpdev-sb-value')
+        input.value = field.value
+        input.onchange = async () => {
+          sandbox.current[code].value = input.value
+          const outcome = await fire(prefix() + 'app.record.' + mode + '.change.' + code, {
+            recordId: recordId(sandbox.current),
+            record: clone(sandbox.current),
+            changes: { field: clone(sandbox.current[code]), row: null },
+          })
+          if (outcome && outcome.event.record) {
+            sandbox.current = clone(outcome.event.record)
+            renderFields(mode)
+          }
+        }
+        row.appendChild(input)
+      } else {
+        row.appendChild(el('div', formatValue(field), 'kpdev-sb-value'))
+      }
+      form.appendChild(row)
+    })
+  }
+
+  const renderRecord = async (mode: string, record: any) => {
+    view = mode
+    clearSpaces()
+    sandbox.current = clone(record)
+    const content = $('kpdev-sb-content')
+    content.innerHTML = ''
+    content.appendChild(el('div', mode === 'create' ? 'レコードの追加' : 'レコード ' + recordId(record), 'kpdev-sb-title'))
+    const form = el('div')
+    form.id = 'kpdev-sb-form'
+    content.appendChild(form)
+    renderFields(mode)
+
+    if (mode === 'edit' || mode === 'create') {
+      const save = el('button', '保存', 'kpdev-sb-primary')
+      save.onclick = async () => {
+        const outcome = await fire(prefix() + 'app.record.' + mode + '.submit', { recordId: recordId(sandbox.current), record: clone(sandbox.current) })
+        if (!outcome || outcome.event.error) {
+          showError(outcome ? outcome.event.error : 'submit イベントでエラーが発生しました')
+          return
+        }
+        const saved = clone(outcome.event.record || sandbox.current)
+        if (mode === 'create') {
+          const nextId = sandbox.records.reduce((max: number, r: any) => Math.max(max, Number(recordId(r)) || 0), 0) + 1
+          saved.$id = { type: '__ID__', value: String(nextId) }
+          sandbox.records.push(saved)
+        } else {
+          const index = sandbox.records.findIndex((r: any) => recordId(r) === recordId(saved))
+          sandbox.records[index] = saved
+        }
+        await fire(prefix() + 'app.record.' + mode + '.submit.success', { recordId: recordId(saved), record: clone(saved) })
+        renderRecord('detail', saved)
+      }
+      const cancel = el('button', 'キャンセル')
+      cancel.onclick = () => (mode === 'create' ? renderIndex() : renderRecord('detail', record))
+      content.appendChild(save)
+      content.appendChild(cancel)
+    } else {
+      const edit = el('button', '編集', 'kpdev-sb-primary')
+      edit.onclick = () => renderRecord('edit', sandbox.current)
+      content.appendChild(edit)
+    }
+
+    const extra: any = { recordId: recordId(sandbox.current), record: clone(sandbox.current) }
+    if (mode === 'create') {
+      delete extra.recordId
+      extra.reuse = false
+    }
+    const outcome = await fire(prefix() + 'app.record.' + mode + '.show', extra)
+    if (outcome && outcome.event.record && mode !== 'detail') {
+      sandbox.current = clone(outcome.event.record)
+      renderFields(mode)
+    }
+  }
+
+  // 空のレコード（追加画面用）
+  const emptyRecord = () => {
+    const record: any = {}
+    fieldCodes(sandbox.records[0]).forEach((code) => {
+      const field = sandbox.records[0][code]
+      record[code] = { type: field.type, value: typeof field.value === 'string' ? '' : clone(field.value) }
+    })
+    return record
+  }
+
+  sandbox.onChange = () => {
+    if (view !== 'index' && $('kpdev-sb-form')) {
+      renderFields(view)
+    }
+  }
+
+  // イベントパネル
+  const EVENTS = [
+    'app.record.index.show',
+    'app.record.index.edit.show',
+    'app.record.index.edit.submit',
+    'app.record.index.delete.submit',
+    'app.record.detail.show',
+    'app.record.detail.delete.submit',
+    'app.record.detail.process.proceed',
+    'app.record.create.show',
+    'app.record.create.submit',
+    'app.record.create.submit.success',
+    'app.record.edit.show',
+    'app.record.edit.submit',
+    'app.record.edit.submit.success',
+    'app.record.print.show',
+    'app.report.show',
+  ]
+  const fillEvents = () => {
+    const select = $('kpdev-sb-event')
+    select.innerHTML = ''
+    EVENTS.forEach((type) => {
+      const option: any = el('option', prefix() + type)
+      option.value = prefix() + type
+      select.appendChild(option)
+    })
+  }
+  const fillRecords = () => {
+    const select = $('kpdev-sb-record')
+    select.innerHTML = ''
+    sandbox.records.forEach((record: any, index: number) => {
+      const option: any = el('option', '$id ' + recordId(record))
+      option.value = String(index)
+      select.appendChild(option)
+    })
+  }
+  $('kpdev-sb-fire').onclick = () => {
+    const type = $('kpdev-sb-event').value
+    const record = sandbox.records[Number($('kpdev-sb-record').value)] || emptyRecord()
+    if (type.indexOf('.index.show') !== -1) {
+      fire(type, { viewType: 'list', viewId: 1, viewName: '（すべて）', records: clone(sandbox.records), offset: 0, size: sandbox.records.length, date: null })
+      return
+    }
+    sandbox.current = clone(record)
+    fire(type, { recordId: recordId(record), record: clone(record) })
+  }
+
+  $('kpdev-sb-nav-index').onclick = () => renderIndex()
+  $('kpdev-sb-nav-detail').onclick = () => sandbox.records[0] && renderRecord('detail', sandbox.current || sandbox.records[0])
+  $('kpdev-sb-nav-edit').onclick = () => sandbox.records[0] && renderRecord('edit', sandbox.current || sandbox.records[0])
+  $('kpdev-sb-nav-create').onclick = () => renderRecord('create', emptyRecord())
+  $('kpdev-sb-device').onchange = (e: any) => {
+    sandbox.mobile = e.target.value === 'mobile'
+    document.body.classList.toggle('kpdev-sb-mobile', sandbox.mobile)
+    fillEvents()
+    renderIndex()
+  }
+
+  if (location.search.indexOf('mobile') !== -1) {
+    sandbox.mobile = true
+    $('kpdev-sb-device').value = 'mobile'
+    document.body.classList.add('kpdev-sb-mobile')
+  }
+  fillEvents()
+  fillRecords()
+  renderIndex()
+}
+
+const SANDBOX_CSS = [
+  'body { margin: 0; font-family: system-ui, -apple-system, sans-serif; background: #f7f9fa; color: #333; font-size: 14px; }',
+  '.kpdev-sb-header { display: flex; gap: 8px; align-items: center; padding: 8px 16px; background: #3498db; color: #fff; }',
+  '.kpdev-sb-header strong { margin-right: 16px; }',
+  '.kpdev-sb-header button, .kpdev-sb-header select { padding: 4px 10px; }',
+  '.kpdev-sb-main { display: flex; gap: 16px; padding: 16px; align-items: flex-start; }',
+  '.kpdev-sb-page { flex: 1; min-width: 0; background: #fff; border: 1px solid #e3e7e8; padding: 16px; }',
+  '.kpdev-sb-mobile .kpdev-sb-page { flex: 0 0 375px; }',
+  '#kpdev-header-menu-space, #kpdev-header-space { min-height: 8px; margin-bottom: 8px; }',
+  '.kpdev-sb-table { border-collapse: collapse; width: 100%; }',
+  '.kpdev-sb-table th, .kpdev-sb-table td { border: 1px solid #e3e7e8; padding: 6px 8px; text-align: left; }',
+  '.kpdev-sb-table tr:hover td { background: #f0f7fc; cursor: pointer; }',
+  '.kpdev-sb-title { font-weight: bold; margin-bottom: 12px; }',
+  '.kpdev-sb-field { margin-bottom: 10px; }',
+  '.kpdev-sb-field label { display: block; color: #888; font-size: 12px; }',
+  '.kpdev-sb-field input { width: 100%; max-width: 320px; padding: 4px; }',
+  '.kpdev-sb-primary { margin-right: 8px; background: #3498db; color: #fff; border: none; padding: 6px 16px; }',
+  '.kpdev-sb-error { margin-bottom: 12px; padding: 8px; background: #fdecea; color: #c62828; }',
+  '.kpdev-sb-panel { flex: 0 0 340px; background: #fff; border: 1px solid #e3e7e8; padding: 12px; }',
+  '.kpdev-sb-panel select, .kpdev-sb-panel button { width: 100%; margin-bottom: 6px; padding: 4px; }',
+  '.kpdev-sb-log-item pre { max-height: 240px; overflow: auto; background: #f5f5f5; padding: 6px; font-size: 12px; }',
+  '.kpdev-sb-log-error { color: #c62828; }',
+].join('\n')
+
+// /__kpdev/sandbox（main エントリを読み込む疑似 kintone 画面）
+function sandboxHTML(): string {
+  const app = readSandboxFixtures().app
+  return '<!DOCTYPE html>' +
+'<html lang="ja">' +
+'<head>' +
+'  <meta charset="UTF-8">' +
+'  <title>kpdev sandbox</title>' +
+'  <style>' + SANDBOX_CSS + '</style>' +
+'  <script type="module" src="/@vite/client"></script>' +
+'  <script src="/__kpdev/kintone-stub.js?sandbox=1"></script>' +
+'</head>' +
+'<body>' +
+'  <div class="kpdev-sb-header">' +
+'    <strong>kpdev sandbox</strong>' +
+'    <span>' + escapeHTML(String(app.name || '')) + ' (appId: ' + escapeHTML(String(app.id)) + ')</span>' +
+'    <button id="kpdev-sb-nav-index">一覧</button>' +
+'    <button id="kpdev-sb-nav-detail">詳細</button>' +
+'    <button id="kpdev-sb-nav-edit">編集</button>' +
+'    <button id="kpdev-sb-nav-create">追加</button>' +
+'    <select id="kpdev-sb-device"><option value="desktop">PC</option><option value="mobile">モバイル</option></select>' +
+'  </div>' +
+'  <div class="kpdev-sb-main">' +
+'    <div class="kpdev-sb-page">' +
+'      <div id="kpdev-header-menu-space"></div>' +
+'      <div id="kpdev-header-space"></div>' +
+'      <div id="kpdev-sb-content"></div>' +
+'      <div id="kpdev-record-spaces"></div>' +
+'    </div>' +
+'    <div class="kpdev-sb-panel">' +
+'      <strong>イベント</strong>' +
+'      <select id="kpdev-sb-event"></select>' +
+'      <select id="kpdev-sb-record"></select>' +
+'      <button id="kpdev-sb-fire">発火</button>' +
+'      <div id="kpdev-sb-log"></div>' +
+'    </div>' +
+'  </div>' +
+'  <script src="/desktop.js"></script>' +
+'  <script>(' + sandboxUI.toString() + ')()</script>' +
+'</body>' +
+'</html>'
+}
+`
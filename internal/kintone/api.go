@@ -11,6 +11,7 @@ import (
 	"net/http/cookiejar"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 type Client struct {
@@ -21,9 +22,15 @@ type Client struct {
 }
 
 func NewClient(domain, username, password string) *Client {
+	return NewClientForURL(fmt.Sprintf("https://%s", domain), username, password)
+}
+
+// NewClientForURL は接続先URLを直接指定してクライアントを作成する
+// kpdev mock-server など https://{domain} 以外に接続する場合に使う
+func NewClientForURL(baseURL, username, password string) *Client {
 	jar, _ := cookiejar.New(nil)
 	return &Client{
-		BaseURL:  fmt.Sprintf("https://%s", domain),
+		BaseURL:  strings.TrimRight(baseURL, "/"),
 		Username: username,
 		Password: password,
 		httpClient: &http.Client{
@@ -0,0 +1,242 @@
+package kintone
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// FieldProperty はフォームのフィールド設定（/k/v1/app/form/fields.json）
+type FieldProperty struct {
+	Type     string                   `json:"type"`
+	Code     string                   `json:"code"`
+	Label    string                   `json:"label"`
+	Required bool                     `json:"required"`
+	Options  map[string]FieldOption   `json:"options,omitempty"`
+	Fields   map[string]FieldProperty `json:"fields,omitempty"` // SUBTABLE のみ
+}
+
+// FieldOption は選択肢フィールドの選択肢
+type FieldOption struct {
+	Label string `json:"label"`
+	Index string `json:"index"`
+}
+
+// GetFormFields はアプリのフィールド設定を取得する
+func (c *Client) GetFormFields(appID string) (map[string]FieldProperty, error) {
+	respBody, err := c.doRequest("GET", "/k/v1/app/form/fields.json?app="+url.QueryEscape(appID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Properties map[string]FieldProperty `json:"properties"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("レスポンス解析エラー: %w", err)
+	}
+
+	return result.Properties, nil
+}
+
+// AppPlugin はアプリに追加されているプラグイン
+type AppPlugin struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+// GetPreviewAppPlugins はアプリ（運用前環境）に追加されているプラグインを取得する
+func (c *Client) GetPreviewAppPlugins(appID string) ([]AppPlugin, error) {
+	respBody, err := c.doRequest("GET", "/k/v1/preview/app/plugins.json?app="+url.QueryEscape(appID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Plugins []AppPlugin `json:"plugins"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("レスポンス解析エラー: %w", err)
+	}
+
+	return result.Plugins, nil
+}
+
+// AddAppPlugins はアプリ（運用前環境）にプラグインを追加する
+func (c *Client) AddAppPlugins(appID string, pluginIDs []string) error {
+	body := map[string]interface{}{
+		"app": appID,
+		"ids": pluginIDs,
+	}
+	_, err := c.doRequest("POST", "/k/v1/preview/app/plugins.json", body)
+	return err
+}
+
+// DeployApp は運用前環境の設定を本番環境に反映し、完了まで待つ
+func (c *Client) DeployApp(appID string) error {
+	body := map[string]interface{}{
+		"apps": []map[string]string{{"app": appID}},
+	}
+	if _, err := c.doRequest("POST", "/k/v1/preview/app/deploy.json", body); err != nil {
+		return err
+	}
+
+	// 反映状況を確認（PROCESSING の間は待つ）
+	for i := 0; i < 60; i++ {
+		respBody, err := c.doRequest("GET", "/k/v1/preview/app/deploy.json?apps[0]="+url.QueryEscape(appID), nil)
+		if err != nil {
+			return err
+		}
+
+		var result struct {
+			Apps []struct {
+				App    string `json:"app"`
+				Status string `json:"status"`
+			} `json:"apps"`
+		}
+		if err := json.Unmarshal(respBody, &result); err != nil {
+			return fmt.Errorf("レスポンス解析エラー: %w", err)
+		}
+		if len(result.Apps) == 0 {
+			return fmt.Errorf("アプリ %s の反映状況を取得できません", appID)
+		}
+
+		switch result.Apps[0].Status {
+		case "SUCCESS":
+			return nil
+		case "FAIL", "CANCEL":
+			return fmt.Errorf("アプリ %s の設定の反映に失敗しました（%s）", appID, result.Apps[0].Status)
+		}
+		time.Sleep(time.Second)
+	}
+
+	return fmt.Errorf("アプリ %s の設定の反映がタイムアウトしました", appID)
+}
+
+// CreatePreviewApp は運用前環境にアプリを作成し、アプリIDを返す
+// 作成したアプリは DeployApp で反映するまで利用できない
+func (c *Client) CreatePreviewApp(name string) (string, error) {
+	body := map[string]interface{}{
+		"name": name,
+	}
+	respBody, err := c.doRequest("POST", "/k/v1/preview/app.json", body)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		App string `json:"app"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", fmt.Errorf("レスポンス解析エラー: %w", err)
+	}
+
+	return result.App, nil
+}
+
+// AddFormFields はアプリ（運用前環境）にフィールドを追加する
+// properties は /k/v1/preview/app/form/fields.json の properties と同じ形式
+func (c *Client) AddFormFields(appID string, properties map[string]interface{}) error {
+	body := map[string]interface{}{
+		"app":        appID,
+		"properties": properties,
+	}
+	_, err := c.doRequest("POST", "/k/v1/preview/app/form/fields.json", body)
+	return err
+}
+
+// CustomizeResource は JavaScript / CSS カスタマイズのファイルまたはURL
+type CustomizeResource struct {
+	Type string `json:"type"` // "FILE" または "URL"
+	URL  string `json:"url,omitempty"`
+	File *struct {
+		ContentType string `json:"contentType"`
+		FileKey     string `json:"fileKey"`
+		Name        string `json:"name"`
+		Size        string `json:"size"`
+	} `json:"file,omitempty"`
+}
+
+// CustomizeTarget は desktop / mobile それぞれの JavaScript・CSS
+type CustomizeTarget struct {
+	JS  []CustomizeResource `json:"js"`
+	CSS []CustomizeResource `json:"css"`
+}
+
+// AppCustomize はアプリの JavaScript / CSS カスタマイズ設定（/k/v1/app/customize.json）
+type AppCustomize struct {
+	Scope   string          `json:"scope"` // ALL / ADMIN / NONE
+	Desktop CustomizeTarget `json:"desktop"`
+	Mobile  CustomizeTarget `json:"mobile"`
+}
+
+// GetAppCustomize はアプリの JavaScript / CSS カスタマイズ設定を取得する
+// FILE の fileKey は DownloadFile でダウンロードできる
+func (c *Client) GetAppCustomize(appID string) (*AppCustomize, error) {
+	respBody, err := c.doRequest("GET", "/k/v1/app/customize.json?app="+url.QueryEscape(appID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result AppCustomize
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("レスポンス解析エラー: %w", err)
+	}
+
+	return &result, nil
+}
+
+// AppInfo はアプリの情報（/k/v1/apps.json）
+type AppInfo struct {
+	AppID   string `json:"appId"`
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	SpaceID string `json:"spaceId"`
+}
+
+// appsLimit は /k/v1/apps.json で1回に指定できるアプリIDの上限
+const appsLimit = 100
+
+// GetApps は指定したアプリの情報を取得する
+func (c *Client) GetApps(appIDs []string) ([]AppInfo, error) {
+	var apps []AppInfo
+	for start := 0; start < len(appIDs); start += appsLimit {
+		end := min(start+appsLimit, len(appIDs))
+
+		query := url.Values{}
+		for i, id := range appIDs[start:end] {
+			query.Set(fmt.Sprintf("ids[%d]", i), id)
+		}
+		respBody, err := c.doRequest("GET", "/k/v1/apps.json?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			Apps []AppInfo `json:"apps"`
+		}
+		if err := json.Unmarshal(respBody, &result); err != nil {
+			return nil, fmt.Errorf("レスポンス解析エラー: %w", err)
+		}
+		apps = append(apps, result.Apps...)
+	}
+	return apps, nil
+}
+
+// GetSpaceName はスペース名を取得する
+func (c *Client) GetSpaceName(spaceID string) (string, error) {
+	respBody, err := c.doRequest("GET", "/k/v1/space.json?id="+url.QueryEscape(spaceID), nil)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", fmt.Errorf("レスポンス解析エラー: %w", err)
+	}
+	return result.Name, nil
+}
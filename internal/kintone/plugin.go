@@ -3,6 +3,7 @@ package kintone
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 )
 
 // ImportPlugin は非公式APIでプラグインをインポートする
@@ -85,3 +86,36 @@ func (c *Client) FindPluginByID(pluginID string) (*PluginInfo, error) {
 
 	return nil, fmt.Errorf("プラグインが見つかりません: %s", pluginID)
 }
+
+// PluginApp はプラグインを追加しているアプリ
+type PluginApp struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// pluginAppsLimit は /k/v1/plugin/apps.json で1回に取得できる上限
+const pluginAppsLimit = 500
+
+// GetPluginApps はプラグインを追加しているアプリをすべて取得する
+func (c *Client) GetPluginApps(pluginID string) ([]PluginApp, error) {
+	var apps []PluginApp
+	for offset := 0; ; offset += pluginAppsLimit {
+		path := fmt.Sprintf("/k/v1/plugin/apps.json?id=%s&offset=%d&limit=%d", url.QueryEscape(pluginID), offset, pluginAppsLimit)
+		respBody, err := c.doRequest("GET", path, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			Apps []PluginApp `json:"apps"`
+		}
+		if err := json.Unmarshal(respBody, &result); err != nil {
+			return nil, fmt.Errorf("レスポンス解析エラー: %w", err)
+		}
+
+		apps = append(apps, result.Apps...)
+		if len(result.Apps) < pluginAppsLimit {
+			return apps, nil
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package kintone
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Record は REST API 形式のレコード（フィールドコード → { type, value }）
+type Record map[string]interface{}
+
+// 1回のリクエストで扱えるレコード数の上限
+const (
+	maxRecordsPerWrite = 100
+	maxRecordsPerRead  = 500
+)
+
+// readOnlyFieldTypes は登録時に指定できないフィールドの種類
+var readOnlyFieldTypes = map[string]bool{
+	"__ID__":          true,
+	"__REVISION__":    true,
+	"RECORD_NUMBER":   true,
+	"CREATOR":         true,
+	"CREATED_TIME":    true,
+	"MODIFIER":        true,
+	"UPDATED_TIME":    true,
+	"STATUS":          true,
+	"STATUS_ASSIGNEE": true,
+	"CATEGORY":        true,
+	"CALC":            true,
+}
+
+// WritableRecord は登録できるフィールドだけを残したレコードを返す
+// $id・$revision や自動計算・システムフィールドを取り除く
+func WritableRecord(record Record) Record {
+	result := Record{}
+	for code, v := range record {
+		if strings.HasPrefix(code, "$") {
+			continue
+		}
+		field, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		t, _ := field["type"].(string)
+		if readOnlyFieldTypes[t] {
+			continue
+		}
+		if t == "SUBTABLE" {
+			result[code] = map[string]interface{}{"value": writableRows(field["value"])}
+			continue
+		}
+		result[code] = map[string]interface{}{"value": field["value"]}
+	}
+	return result
+}
+
+// writableRows はテーブルの行から行IDと登録できないフィールドを取り除く
+// （別のアプリから取得した行IDを指定すると登録に失敗するため）
+func writableRows(value interface{}) []interface{} {
+	rows, _ := value.([]interface{})
+	result := make([]interface{}, 0, len(rows))
+	for _, row := range rows {
+		r, ok := row.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		cells, _ := r["value"].(map[string]interface{})
+		result = append(result, map[string]interface{}{"value": map[string]interface{}(WritableRecord(cells))})
+	}
+	return result
+}
+
+// AddRecords はレコードを登録する（100件ずつ分割して送信）
+func (c *Client) AddRecords(appID string, records []Record) error {
+	for start := 0; start < len(records); start += maxRecordsPerWrite {
+		end := start + maxRecordsPerWrite
+		if end > len(records) {
+			end = len(records)
+		}
+
+		body := map[string]interface{}{
+			"app":     appID,
+			"records": records[start:end],
+		}
+		if _, err := c.doRequest("POST", "/k/v1/records.json", body); err != nil {
+			return fmt.Errorf("%d〜%d件目: %w", start+1, end, err)
+		}
+	}
+	return nil
+}
+
+// GetRecordsByCursor はカーソルAPIで条件に一致するレコードをすべて取得する
+// offset の上限（10,000件）を超えるアプリでも取得できる。query に limit・offset は指定できない
+func (c *Client) GetRecordsByCursor(appID, query string, fields []string) ([]Record, error) {
+	body := map[string]interface{}{
+		"app":   appID,
+		"query": query,
+		"size":  maxRecordsPerRead,
+	}
+	if len(fields) > 0 {
+		body["fields"] = fields
+	}
+	respBody, err := c.doRequest("POST", "/k/v1/records/cursor.json", body)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(respBody, &cursor); err != nil {
+		return nil, fmt.Errorf("レスポンス解析エラー: %w", err)
+	}
+
+	var all []Record
+	for {
+		respBody, err := c.doRequest("GET", "/k/v1/records/cursor.json?id="+url.QueryEscape(cursor.ID), nil)
+		if err != nil {
+			// 途中で失敗したカーソルは残さない（同時に作成できる数に上限がある）
+			c.doRequest("DELETE", "/k/v1/records/cursor.json", map[string]string{"id": cursor.ID})
+			return nil, err
+		}
+
+		var result struct {
+			Records []Record `json:"records"`
+			Next    bool     `json:"next"`
+		}
+		if err := json.Unmarshal(respBody, &result); err != nil {
+			c.doRequest("DELETE", "/k/v1/records/cursor.json", map[string]string{"id": cursor.ID})
+			return nil, fmt.Errorf("レスポンス解析エラー: %w", err)
+		}
+
+		all = append(all, result.Records...)
+		if !result.Next {
+			// 最後まで読み切ったカーソルは kintone 側で自動的に削除される
+			return all, nil
+		}
+	}
+}
+
+// DownloadFile は添付ファイルをダウンロードする
+// fileKey はレコード取得時の FILE フィールドの値（アップロード時の fileKey とは異なる）
+func (c *Client) DownloadFile(fileKey string) ([]byte, error) {
+	return c.doRequest("GET", "/k/v1/file.json?fileKey="+url.QueryEscape(fileKey), nil)
+}
+
+// DeleteAllRecords はアプリのレコードをすべて削除し、削除した件数を返す
+// offset の上限（10,000件）を超えるアプリでも削除できるよう、ID はカーソルAPIで取得する
+func (c *Client) DeleteAllRecords(appID string) (int, error) {
+	records, err := c.GetRecordsByCursor(appID, "", []string{"$id"})
+	if err != nil {
+		return 0, err
+	}
+
+	ids := make([]string, 0, len(records))
+	for _, r := range records {
+		if field, ok := r["$id"].(map[string]interface{}); ok {
+			ids = append(ids, fmt.Sprintf("%v", field["value"]))
+		}
+	}
+
+	for start := 0; start < len(ids); start += maxRecordsPerWrite {
+		end := start + maxRecordsPerWrite
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		body := map[string]interface{}{
+			"app": appID,
+			"ids": ids[start:end],
+		}
+		if _, err := c.doRequest("DELETE", "/k/v1/records.json", body); err != nil {
+			return start, err
+		}
+	}
+
+	return len(ids), nil
+}
+
+// LoadRecordsFile はレコードのフィクスチャファイルを読み込む
+// レコードの配列、または { "records": [...] } 形式（kpdev sandbox のフィクスチャと同じ）を受け付ける
+func LoadRecordsFile(path string) ([]Record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	if err := json.Unmarshal(data, &records); err == nil {
+		return records, nil
+	}
+
+	var wrapped struct {
+		Records []Record `json:"records"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return nil, fmt.Errorf("%s の解析エラー: %w", path, err)
+	}
+	return wrapped.Records, nil
+}
+
+// MapFileFields はレコード（テーブル内を含む）の FILE フィールドの値を fn の戻り値で置き換える
+// フィクスチャの書き出し・読み込み時に添付ファイルを変換するために使う
+func MapFileFields(record Record, fn func(code string, files []interface{}) ([]interface{}, error)) error {
+	for code, v := range record {
+		field, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		switch field["type"] {
+		case "FILE":
+			files, _ := field["value"].([]interface{})
+			if len(files) == 0 {
+				continue
+			}
+			mapped, err := fn(code, files)
+			if err != nil {
+				return err
+			}
+			field["value"] = mapped
+		case "SUBTABLE":
+			rows, _ := field["value"].([]interface{})
+			for _, row := range rows {
+				r, ok := row.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				cells, _ := r["value"].(map[string]interface{})
+				if err := MapFileFields(cells, fn); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
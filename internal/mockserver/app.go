@@ -0,0 +1,330 @@
+package mockserver
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// App はモックサーバー上のアプリ
+type App struct {
+	ID               string                            `json:"id"`
+	Name             string                            `json:"name"`
+	PreviewPlugins   []string                          `json:"previewPlugins"`
+	Plugins          []string                          `json:"plugins"`
+	PreviewFields    map[string]map[string]interface{} `json:"previewFields"`
+	Fields           map[string]map[string]interface{} `json:"fields"`
+	Records          []map[string]interface{}          `json:"records"`
+	PreviewCustomize map[string]interface{}            `json:"previewCustomize,omitempty"`
+	Customize        map[string]interface{}            `json:"customize,omitempty"`
+	Revision         int                               `json:"revision"`
+
+	nextRecordID int
+}
+
+// app は指定IDのアプリを返す（存在しなければ作成する）
+// 呼び出し側で s.mu を保持していること
+func (s *Server) app(id string) *App {
+	a, ok := s.apps[id]
+	if !ok {
+		a = &App{
+			ID:             id,
+			PreviewPlugins: []string{},
+			Plugins:        []string{},
+			PreviewFields:  map[string]map[string]interface{}{},
+			Fields:         map[string]map[string]interface{}{},
+			Records:        []map[string]interface{}{},
+		}
+		s.apps[id] = a
+	}
+	return a
+}
+
+// validAppID は数値のアプリIDかどうかを返す
+func validAppID(id string) bool {
+	n, err := strconv.Atoi(id)
+	return err == nil && n > 0
+}
+
+// /k/v1/preview/app/plugins.json（GET: 一覧 / POST: 追加）
+func (s *Server) serveAppPlugins(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		appID := r.URL.Query().Get("app")
+		if !validAppID(appID) {
+			writeError(w, http.StatusBadRequest, "CB_VA01", "app を指定してください。")
+			return
+		}
+
+		s.mu.Lock()
+		a := s.app(appID)
+		type appPluginJSON struct {
+			ID      string `json:"id"`
+			Name    string `json:"name"`
+			Enabled bool   `json:"enabled"`
+		}
+		list := make([]appPluginJSON, 0, len(a.PreviewPlugins))
+		for _, id := range a.PreviewPlugins {
+			name := ""
+			if p, ok := s.plugins[id]; ok {
+				name = p.Name
+			}
+			list = append(list, appPluginJSON{ID: id, Name: name, Enabled: true})
+		}
+		revision := a.Revision
+		s.mu.Unlock()
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"plugins":  list,
+			"revision": strconv.Itoa(revision),
+		})
+		return
+	}
+
+	var body struct {
+		App json.Number `json:"app"`
+		IDs []string    `json:"ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || !validAppID(body.App.String()) || len(body.IDs) == 0 {
+		writeError(w, http.StatusBadRequest, "CB_VA01", "app と ids を指定してください。")
+		return
+	}
+	appID := body.App.String()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a := s.app(appID)
+	switch r.Method {
+	case http.MethodPost:
+		for _, id := range body.IDs {
+			if _, ok := s.plugins[id]; !ok {
+				writeError(w, http.StatusBadRequest, "GAIA_PL18", "プラグイン（"+id+"）がインストールされていません。")
+				return
+			}
+		}
+		for _, id := range body.IDs {
+			if !containsString(a.PreviewPlugins, id) {
+				a.PreviewPlugins = append(a.PreviewPlugins, id)
+			}
+		}
+		s.logf("POST /k/v1/preview/app/plugins.json -> アプリ %s に %v を追加", appID, body.IDs)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "CB_NO02", "許可されていないメソッドです。")
+		return
+	}
+	a.Revision++
+
+	writeJSON(w, http.StatusOK, map[string]string{"revision": strconv.Itoa(a.Revision)})
+}
+
+// /k/v1/preview/app/deploy.json（POST: 反映 / GET: 反映状況）
+// モックでは反映は即座に完了する
+func (s *Server) serveAppDeploy(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		appID := r.URL.Query().Get("apps[0]")
+		if !validAppID(appID) {
+			writeError(w, http.StatusBadRequest, "CB_VA01", "apps を指定してください。")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"apps": []map[string]string{{"app": appID, "status": "SUCCESS"}},
+		})
+		return
+	}
+
+	var body struct {
+		Apps []struct {
+			App json.Number `json:"app"`
+		} `json:"apps"`
+		Revert bool `json:"revert"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Apps) == 0 {
+		writeError(w, http.StatusBadRequest, "CB_VA01", "apps を指定してください。")
+		return
+	}
+
+	s.mu.Lock()
+	for _, item := range body.Apps {
+		a := s.app(item.App.String())
+		if body.Revert {
+			a.PreviewPlugins = append([]string{}, a.Plugins...)
+			a.PreviewFields = copyFields(a.Fields)
+			a.PreviewCustomize = a.Customize
+		} else {
+			a.Plugins = append([]string{}, a.PreviewPlugins...)
+			a.Fields = copyFields(a.PreviewFields)
+			a.Customize = a.PreviewCustomize
+		}
+		s.logf("POST /k/v1/preview/app/deploy.json -> アプリ %s（プラグイン %d 件）", a.ID, len(a.Plugins))
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+func containsString(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+// /k/v1/app/customize.json（GET）と /k/v1/preview/app/customize.json（GET / PUT）
+func (s *Server) serveAppCustomize(w http.ResponseWriter, r *http.Request, preview bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method == http.MethodGet {
+		appID := r.URL.Query().Get("app")
+		a, ok := s.apps[appID]
+		if !ok {
+			writeError(w, http.StatusNotFound, "GAIA_AP01", "指定したアプリ（id: "+appID+"）が見つかりません。")
+			return
+		}
+		customize := a.Customize
+		if preview {
+			customize = a.PreviewCustomize
+		}
+		if customize == nil {
+			empty := map[string]interface{}{"js": []interface{}{}, "css": []interface{}{}}
+			customize = map[string]interface{}{"scope": "ALL", "desktop": empty, "mobile": empty}
+		}
+		result := map[string]interface{}{"revision": strconv.Itoa(a.Revision)}
+		for k, v := range customize {
+			result[k] = v
+		}
+		writeJSON(w, http.StatusOK, result)
+		return
+	}
+
+	if !preview || r.Method != http.MethodPut {
+		writeError(w, http.StatusMethodNotAllowed, "CB_NO02", "許可されていないメソッドです。")
+		return
+	}
+
+	var body struct {
+		App     json.Number                         `json:"app"`
+		Scope   string                              `json:"scope"`
+		Desktop map[string][]map[string]interface{} `json:"desktop"`
+		Mobile  map[string][]map[string]interface{} `json:"mobile"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "CB_VA01", "リクエストの形式が不正です。")
+		return
+	}
+	a, ok := s.apps[body.App.String()]
+	if !ok {
+		writeError(w, http.StatusNotFound, "GAIA_AP01", "指定したアプリ（id: "+body.App.String()+"）が見つかりません。")
+		return
+	}
+
+	// FILE はアップロード済みファイルの情報に置き換える
+	resolve := func(target map[string][]map[string]interface{}) (map[string]interface{}, error) {
+		result := map[string]interface{}{"js": []interface{}{}, "css": []interface{}{}}
+		for _, kind := range []string{"js", "css"} {
+			resources := []interface{}{}
+			for _, res := range target[kind] {
+				if res["type"] == "FILE" {
+					file, _ := res["file"].(map[string]interface{})
+					fileKey, _ := file["fileKey"].(string)
+					data, ok := s.files[fileKey]
+					if !ok {
+						return nil, errors.New("fileKey「" + fileKey + "」のファイルが見つかりません。")
+					}
+					res = map[string]interface{}{
+						"type": "FILE",
+						"file": map[string]interface{}{
+							"contentType": "application/octet-stream",
+							"fileKey":     fileKey,
+							"name":        s.fileNames[fileKey],
+							"size":        strconv.Itoa(len(data)),
+						},
+					}
+				}
+				resources = append(resources, res)
+			}
+			result[kind] = resources
+		}
+		return result, nil
+	}
+
+	desktop, err := resolve(body.Desktop)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "CB_VA01", err.Error())
+		return
+	}
+	mobile, err := resolve(body.Mobile)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "CB_VA01", err.Error())
+		return
+	}
+	if body.Scope == "" {
+		body.Scope = "ALL"
+	}
+	a.PreviewCustomize = map[string]interface{}{"scope": body.Scope, "desktop": desktop, "mobile": mobile}
+	a.Revision++
+
+	s.logf("PUT /k/v1/preview/app/customize.json -> アプリ %s", a.ID)
+	writeJSON(w, http.StatusOK, map[string]string{"revision": strconv.Itoa(a.Revision)})
+}
+
+// GET /k/v1/plugin/apps.json（プラグインを追加しているアプリ。運用環境に反映済みのもの）
+func (s *Server) servePluginApps(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	pluginID := q.Get("id")
+	if pluginID == "" {
+		writeError(w, http.StatusBadRequest, "CB_VA01", "id を指定してください。")
+		return
+	}
+	offset, _ := strconv.Atoi(q.Get("offset"))
+	limit, err := strconv.Atoi(q.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 100
+	}
+
+	type pluginAppJSON struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	list := []pluginAppJSON{}
+	for _, a := range s.Apps() {
+		if containsString(a.Plugins, pluginID) {
+			list = append(list, pluginAppJSON{ID: a.ID, Name: a.Name})
+		}
+	}
+	list = list[min(offset, len(list)):min(offset+limit, len(list))]
+
+	s.logf("GET /k/v1/plugin/apps.json %s -> %d 件", pluginID, len(list))
+	writeJSON(w, http.StatusOK, map[string]interface{}{"apps": list})
+}
+
+// GET /k/v1/apps.json（ids[n] で指定したアプリ。モックのアプリはスペースに属さない）
+func (s *Server) serveAppsInfo(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	ids := map[string]bool{}
+	for key, values := range q {
+		if strings.HasPrefix(key, "ids[") {
+			for _, v := range values {
+				ids[v] = true
+			}
+		}
+	}
+
+	type appJSON struct {
+		AppID   string      `json:"appId"`
+		Code    string      `json:"code"`
+		Name    string      `json:"name"`
+		SpaceID interface{} `json:"spaceId"`
+	}
+	list := []appJSON{}
+	for _, a := range s.Apps() {
+		if len(ids) == 0 || ids[a.ID] {
+			list = append(list, appJSON{AppID: a.ID, Name: a.Name})
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"apps": list})
+}
@@ -0,0 +1,90 @@
+package mockserver
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Failure はリクエストに対して注入する失敗
+type Failure struct {
+	// Path は対象の API パス（"/k/v1/file.json" のほか "file.json" のような末尾一致も可）
+	Path string `json:"path"`
+	// Status は返す HTTP ステータス
+	Status int `json:"status"`
+	// Code / Message は kintone 形式のエラーレスポンスに含める値（省略時は既定値）
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+	// Count は失敗させる回数（0 は無制限）
+	Count int `json:"count,omitempty"`
+}
+
+// ParseFailure は "import.json=500" や "file.json=503x2" 形式の指定を解析する
+// x の後ろは失敗させる回数（省略時は無制限）
+func ParseFailure(spec string) (Failure, error) {
+	path, rest, ok := strings.Cut(spec, "=")
+	if !ok || path == "" || rest == "" {
+		return Failure{}, fmt.Errorf("失敗の指定が不正です（例: import.json=500, file.json=503x2）: %s", spec)
+	}
+
+	statusStr, countStr, hasCount := strings.Cut(rest, "x")
+	status, err := strconv.Atoi(statusStr)
+	if err != nil || status < 400 || status > 599 {
+		return Failure{}, fmt.Errorf("ステータスは 400〜599 で指定してください: %s", spec)
+	}
+
+	f := Failure{Path: path, Status: status}
+	if hasCount {
+		count, err := strconv.Atoi(countStr)
+		if err != nil || count < 1 {
+			return Failure{}, fmt.Errorf("回数は 1 以上で指定してください: %s", spec)
+		}
+		f.Count = count
+	}
+	return f, nil
+}
+
+// InjectFailure は以降のリクエストに失敗を注入する
+func (s *Server) InjectFailure(f Failure) {
+	if f.Status == 0 {
+		f.Status = http.StatusInternalServerError
+	}
+	if f.Code == "" {
+		f.Code = "KPDEV_MOCK_FAILURE"
+	}
+	if f.Message == "" {
+		f.Message = "kpdev mock-server が注入した失敗です。"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, &f)
+}
+
+// ClearFailures は注入した失敗をすべて解除する
+func (s *Server) ClearFailures() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = nil
+}
+
+// takeFailure は path に該当する失敗を取り出す（回数指定があれば消費する）
+func (s *Server) takeFailure(path string) *Failure {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, f := range s.failures {
+		if f.Path != path && !strings.HasSuffix(path, "/"+strings.TrimPrefix(f.Path, "/")) {
+			continue
+		}
+		if f.Count > 0 {
+			f.Count--
+			if f.Count == 0 {
+				s.failures = append(s.failures[:i], s.failures[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
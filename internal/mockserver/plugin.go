@@ -0,0 +1,147 @@
+package mockserver
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/kintone/kpdev/internal/generator"
+)
+
+// PluginInfo は検証済みプラグインZIPから読み取った情報
+type PluginInfo struct {
+	ID          string
+	Name        string
+	Description string
+	Version     string
+}
+
+// VerifyPluginZip はプラグインZIPの署名と manifest.json を検証する
+// kintone と同様に contents.zip の SHA1 を PUBKEY で検証し、プラグインIDは PUBKEY から求める
+func VerifyPluginZip(data []byte) (*PluginInfo, error) {
+	outer, err := readZip(data)
+	if err != nil {
+		return nil, fmt.Errorf("プラグインZIPを読み込めません: %w", err)
+	}
+
+	for _, name := range []string{"contents.zip", "PUBKEY", "SIGNATURE"} {
+		if _, ok := outer[name]; !ok {
+			return nil, fmt.Errorf("プラグインZIPに %s がありません", name)
+		}
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(outer["PUBKEY"])
+	if err != nil {
+		return nil, fmt.Errorf("PUBKEY を解析できません: %w", err)
+	}
+	rsaPub, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("PUBKEY が RSA 公開鍵ではありません")
+	}
+
+	hash := sha1.Sum(outer["contents.zip"])
+	if err := rsa.VerifyPKCS1v15(rsaPub, crypto.SHA1, hash[:], outer["SIGNATURE"]); err != nil {
+		return nil, fmt.Errorf("署名の検証に失敗しました: %w", err)
+	}
+
+	contents, err := readZip(outer["contents.zip"])
+	if err != nil {
+		return nil, fmt.Errorf("contents.zip を読み込めません: %w", err)
+	}
+
+	manifestData, ok := contents["manifest.json"]
+	if !ok {
+		return nil, fmt.Errorf("contents.zip に manifest.json がありません")
+	}
+
+	var manifest struct {
+		Version     interface{}       `json:"version"`
+		Icon        string            `json:"icon"`
+		Name        map[string]string `json:"name"`
+		Description map[string]string `json:"description"`
+		Desktop     manifestFiles     `json:"desktop"`
+		Mobile      manifestFiles     `json:"mobile"`
+		Config      struct {
+			HTML string `json:"html"`
+			manifestFiles
+		} `json:"config"`
+	}
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, fmt.Errorf("manifest.json を解析できません: %w", err)
+	}
+	if manifest.Version == nil {
+		return nil, fmt.Errorf("manifest.json に version がありません")
+	}
+
+	// manifest.json が参照するファイルが同梱されているか確認（URL 指定は対象外）
+	refs := []string{manifest.Icon, manifest.Config.HTML}
+	for _, files := range []manifestFiles{manifest.Desktop, manifest.Mobile, manifest.Config.manifestFiles} {
+		refs = append(refs, files.JS...)
+		refs = append(refs, files.CSS...)
+	}
+	for _, ref := range refs {
+		if ref == "" || isURL(ref) {
+			continue
+		}
+		if _, ok := contents[ref]; !ok {
+			return nil, fmt.Errorf("manifest.json が参照する %s が contents.zip にありません", ref)
+		}
+	}
+
+	return &PluginInfo{
+		ID:          generator.PluginIDFromPublicKeyDER(outer["PUBKEY"]),
+		Name:        localized(manifest.Name),
+		Description: localized(manifest.Description),
+		Version:     fmt.Sprintf("%v", manifest.Version),
+	}, nil
+}
+
+type manifestFiles struct {
+	JS  []string `json:"js"`
+	CSS []string `json:"css"`
+}
+
+func readZip(data []byte) (map[string][]byte, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{}
+	for _, f := range r.File {
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		files[f.Name] = content
+	}
+	return files, nil
+}
+
+// localized は manifest の多言語表記から表示名を選ぶ
+func localized(values map[string]string) string {
+	for _, lang := range []string{"ja", "en", "zh"} {
+		if v := values[lang]; v != "" {
+			return v
+		}
+	}
+	for _, v := range values {
+		return v
+	}
+	return ""
+}
+
+func isURL(ref string) bool {
+	return len(ref) > 8 && (ref[:7] == "http://" || ref[:8] == "https://")
+}
@@ -0,0 +1,379 @@
+package mockserver
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// POST /k/v1/preview/app.json
+func (s *Server) serveCreateApp(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Name == "" {
+		writeError(w, http.StatusBadRequest, "CB_VA01", "name を指定してください。")
+		return
+	}
+
+	s.mu.Lock()
+	next := 1
+	for id := range s.apps {
+		if n, _ := strconv.Atoi(id); n >= next {
+			next = n + 1
+		}
+	}
+	a := s.app(strconv.Itoa(next))
+	a.Name = body.Name
+	a.Revision = 1
+	s.mu.Unlock()
+
+	s.logf("POST /k/v1/preview/app.json -> アプリ %s「%s」を作成", a.ID, body.Name)
+	writeJSON(w, http.StatusOK, map[string]string{"app": a.ID, "revision": "1"})
+}
+
+// /k/v1/app/form/fields.json（GET）と /k/v1/preview/app/form/fields.json（GET / POST）
+func (s *Server) serveFormFields(w http.ResponseWriter, r *http.Request, preview bool) {
+	if r.Method == http.MethodGet {
+		appID := r.URL.Query().Get("app")
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		a, ok := s.apps[appID]
+		if !ok {
+			writeError(w, http.StatusNotFound, "GAIA_AP01", "指定したアプリ（id: "+appID+"）が見つかりません。")
+			return
+		}
+		fields := a.Fields
+		if preview {
+			fields = a.PreviewFields
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"properties": fields,
+			"revision":   strconv.Itoa(a.Revision),
+		})
+		return
+	}
+
+	if !preview || r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "CB_NO02", "許可されていないメソッドです。")
+		return
+	}
+
+	var body struct {
+		App        json.Number                       `json:"app"`
+		Properties map[string]map[string]interface{} `json:"properties"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Properties) == 0 {
+		writeError(w, http.StatusBadRequest, "CB_VA01", "app と properties を指定してください。")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.apps[body.App.String()]
+	if !ok {
+		writeError(w, http.StatusNotFound, "GAIA_AP01", "指定したアプリ（id: "+body.App.String()+"）が見つかりません。")
+		return
+	}
+	for code, field := range body.Properties {
+		if _, exists := a.PreviewFields[code]; exists {
+			writeError(w, http.StatusBadRequest, "GAIA_FC01", "フィールドコード「"+code+"」は既に使用されています。")
+			return
+		}
+		if t, _ := field["type"].(string); t == "" {
+			writeError(w, http.StatusBadRequest, "CB_VA01", "properties."+code+".type を指定してください。")
+			return
+		}
+	}
+	for code, field := range body.Properties {
+		a.PreviewFields[code] = field
+	}
+	a.Revision++
+
+	s.logf("POST /k/v1/preview/app/form/fields.json -> アプリ %s にフィールド %d 件を追加", a.ID, len(body.Properties))
+	writeJSON(w, http.StatusOK, map[string]string{"revision": strconv.Itoa(a.Revision)})
+}
+
+// queryLimitPattern は query の limit / offset を取り出す（それ以外の条件は無視する）
+var queryLimitPattern = regexp.MustCompile(`limit\s+(\d+)(?:\s+offset\s+(\d+))?`)
+
+// /k/v1/records.json（GET: 取得 / POST: 登録 / DELETE: 削除）
+func (s *Server) serveRecords(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		q := r.URL.Query()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		a, ok := s.apps[q.Get("app")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "GAIA_AP01", "指定したアプリ（id: "+q.Get("app")+"）が見つかりません。")
+			return
+		}
+
+		limit, offset := 100, 0
+		if m := queryLimitPattern.FindStringSubmatch(q.Get("query")); m != nil {
+			limit, _ = strconv.Atoi(m[1])
+			if m[2] != "" {
+				offset, _ = strconv.Atoi(m[2])
+			}
+		}
+
+		var fields []string
+		for i := 0; q.Has("fields[" + strconv.Itoa(i) + "]"); i++ {
+			fields = append(fields, q.Get("fields["+strconv.Itoa(i)+"]"))
+		}
+
+		records := []map[string]interface{}{}
+		for i := offset; i < len(a.Records) && i < offset+limit; i++ {
+			records = append(records, pickFields(a.Records[i], fields))
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"records":    records,
+			"totalCount": nil,
+		})
+		return
+	}
+
+	var body struct {
+		App     json.Number              `json:"app"`
+		Records []map[string]interface{} `json:"records"`
+		IDs     []json.Number            `json:"ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "CB_VA01", "リクエストの形式が不正です。")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.apps[body.App.String()]
+	if !ok {
+		writeError(w, http.StatusNotFound, "GAIA_AP01", "指定したアプリ（id: "+body.App.String()+"）が見つかりません。")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		if len(body.Records) > 100 {
+			writeError(w, http.StatusBadRequest, "CB_VA01", "一度に登録できるレコードは100件までです。")
+			return
+		}
+		for i, record := range body.Records {
+			for code := range record {
+				if _, ok := a.Fields[code]; !ok {
+					writeError(w, http.StatusBadRequest, "CB_VA01", "records["+strconv.Itoa(i)+"]: フィールド「"+code+"」は存在しません。")
+					return
+				}
+			}
+		}
+
+		for i, record := range body.Records {
+			for code, v := range record {
+				if a.Fields[code]["type"] != "FILE" {
+					continue
+				}
+				if err := s.resolveFileValue(v); err != nil {
+					writeError(w, http.StatusBadRequest, "CB_VA01", "records["+strconv.Itoa(i)+"]."+code+": "+err.Error())
+					return
+				}
+			}
+		}
+
+		ids := []string{}
+		revisions := []string{}
+		for _, record := range body.Records {
+			a.nextRecordID++
+			id := strconv.Itoa(a.nextRecordID)
+			stored := map[string]interface{}{
+				"$id":       map[string]interface{}{"type": "__ID__", "value": id},
+				"$revision": map[string]interface{}{"type": "__REVISION__", "value": "1"},
+			}
+			for code, v := range record {
+				value := v
+				if field, ok := v.(map[string]interface{}); ok {
+					value = field["value"]
+				}
+				stored[code] = map[string]interface{}{"type": a.Fields[code]["type"], "value": value}
+			}
+			a.Records = append(a.Records, stored)
+			ids = append(ids, id)
+			revisions = append(revisions, "1")
+		}
+		s.logf("POST /k/v1/records.json -> アプリ %s に %d 件を登録", a.ID, len(ids))
+		writeJSON(w, http.StatusOK, map[string]interface{}{"ids": ids, "revisions": revisions})
+
+	case http.MethodDelete:
+		remove := map[string]bool{}
+		for _, id := range body.IDs {
+			remove[id.String()] = true
+		}
+		kept := []map[string]interface{}{}
+		for _, record := range a.Records {
+			if !remove[recordID(record)] {
+				kept = append(kept, record)
+			}
+		}
+		s.logf("DELETE /k/v1/records.json -> アプリ %s から %d 件を削除", a.ID, len(a.Records)-len(kept))
+		a.Records = kept
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "CB_NO02", "許可されていないメソッドです。")
+	}
+}
+
+// resolveFileValue は FILE フィールドの値（fileKey の配列）をアップロード済みファイルの情報に置き換える
+// 呼び出し側で s.mu を保持していること
+func (s *Server) resolveFileValue(v interface{}) error {
+	field, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	files, _ := field["value"].([]interface{})
+	resolved := make([]interface{}, 0, len(files))
+	for _, f := range files {
+		file, _ := f.(map[string]interface{})
+		fileKey, _ := file["fileKey"].(string)
+		data, ok := s.files[fileKey]
+		if !ok {
+			return errors.New("fileKey「" + fileKey + "」のファイルが見つかりません。")
+		}
+		resolved = append(resolved, map[string]interface{}{
+			"contentType": "application/octet-stream",
+			"fileKey":     fileKey,
+			"name":        s.fileNames[fileKey],
+			"size":        strconv.Itoa(len(data)),
+		})
+	}
+	field["value"] = resolved
+	return nil
+}
+
+// GET /k/v1/file.json
+func (s *Server) serveDownload(w http.ResponseWriter, r *http.Request) {
+	fileKey := r.URL.Query().Get("fileKey")
+
+	s.mu.Lock()
+	data, ok := s.files[fileKey]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "GAIA_BL01", "指定したファイル（id: "+fileKey+"）が見つかりません。")
+		return
+	}
+	s.logf("GET /k/v1/file.json %s (%d bytes)", fileKey, len(data))
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Write(data)
+}
+
+// recordCursor はカーソルAPIで作成したカーソル（作成時点のレコードを保持する）
+type recordCursor struct {
+	records []map[string]interface{}
+	size    int
+}
+
+// /k/v1/records/cursor.json（POST: 作成 / GET: 取得 / DELETE: 削除）
+// query は解釈せず、アプリのすべてのレコードを返す
+func (s *Server) serveRecordCursor(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPost:
+		var body struct {
+			App    json.Number `json:"app"`
+			Fields []string    `json:"fields"`
+			Size   int         `json:"size"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, "CB_VA01", "リクエストの形式が不正です。")
+			return
+		}
+		a, ok := s.apps[body.App.String()]
+		if !ok {
+			writeError(w, http.StatusNotFound, "GAIA_AP01", "指定したアプリ（id: "+body.App.String()+"）が見つかりません。")
+			return
+		}
+		if body.Size <= 0 {
+			body.Size = 100
+		}
+
+		records := make([]map[string]interface{}, 0, len(a.Records))
+		for _, record := range a.Records {
+			records = append(records, pickFields(record, body.Fields))
+		}
+		s.nextCursor++
+		id := "mock-cursor-" + strconv.Itoa(s.nextCursor)
+		s.cursors[id] = &recordCursor{records: records, size: body.Size}
+
+		s.logf("POST /k/v1/records/cursor.json -> %s（%d件）", id, len(records))
+		writeJSON(w, http.StatusOK, map[string]string{"id": id, "totalCount": strconv.Itoa(len(records))})
+
+	case http.MethodGet:
+		id := r.URL.Query().Get("id")
+		c, ok := s.cursors[id]
+		if !ok {
+			writeError(w, http.StatusBadRequest, "GAIA_CO02", "指定したカーソルが見つかりません。")
+			return
+		}
+		n := c.size
+		if n > len(c.records) {
+			n = len(c.records)
+		}
+		page := c.records[:n]
+		c.records = c.records[n:]
+		next := len(c.records) > 0
+		if !next {
+			delete(s.cursors, id)
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"records": page, "next": next})
+
+	case http.MethodDelete:
+		var body struct {
+			ID string `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		delete(s.cursors, body.ID)
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "CB_NO02", "許可されていないメソッドです。")
+	}
+}
+
+func recordID(record map[string]interface{}) string {
+	if field, ok := record["$id"].(map[string]interface{}); ok {
+		if v, ok := field["value"].(string); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// pickFields は fields が指定されていれば該当するフィールドだけを返す
+func pickFields(record map[string]interface{}, fields []string) map[string]interface{} {
+	if len(fields) == 0 {
+		return record
+	}
+	picked := map[string]interface{}{}
+	for _, code := range fields {
+		if v, ok := record[strings.TrimSpace(code)]; ok {
+			picked[code] = v
+		}
+	}
+	return picked
+}
+
+func copyFields(fields map[string]map[string]interface{}) map[string]map[string]interface{} {
+	copied := make(map[string]map[string]interface{}, len(fields))
+	for code, field := range fields {
+		copied[code] = field
+	}
+	return copied
+}
@@ -0,0 +1,358 @@
+package mockserver
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxUploadSize はアップロードを受け付けるファイルの上限
+const maxUploadSize = 32 << 20
+
+// Options はモックサーバーの設定
+type Options struct {
+	// Username / Password を指定するとパスワード認証を検証する（空なら検証しない）
+	Username string
+	Password string
+	// Logf を指定するとリクエストごとにログを出力する
+	Logf func(format string, args ...interface{})
+}
+
+// Plugin はモックサーバーにインストールされたプラグイン
+type Plugin struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Version     string    `json:"version"`
+	Revision    int       `json:"revision"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// Server は kintone のプラグイン関連 API を模したテスト用サーバー
+// http.Handler として httptest.NewServer にもそのまま渡せる
+type Server struct {
+	opts Options
+
+	mu         sync.Mutex
+	files      map[string][]byte
+	fileNames  map[string]string
+	nextFile   int
+	plugins    map[string]*Plugin
+	apps       map[string]*App
+	cursors    map[string]*recordCursor
+	nextCursor int
+	failures   []*Failure
+}
+
+// New はモックサーバーを作成する
+func New(opts Options) *Server {
+	s := &Server{opts: opts}
+	s.Reset()
+	return s
+}
+
+// Reset はアップロード済みファイル・インストール済みプラグイン・注入した失敗をすべて消去する
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.files = map[string][]byte{}
+	s.fileNames = map[string]string{}
+	s.nextFile = 0
+	s.plugins = map[string]*Plugin{}
+	s.apps = map[string]*App{}
+	s.cursors = map[string]*recordCursor{}
+	s.nextCursor = 0
+	s.failures = nil
+}
+
+// Plugins はインストール済みプラグインを ID 順で返す
+func (s *Server) Plugins() []Plugin {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	plugins := make([]Plugin, 0, len(s.plugins))
+	for _, p := range s.plugins {
+		plugins = append(plugins, *p)
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].ID < plugins[j].ID })
+	return plugins
+}
+
+// Apps はプラグインを追加したアプリを ID 順で返す
+func (s *Server) Apps() []App {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	apps := make([]App, 0, len(s.apps))
+	for _, a := range s.apps {
+		apps = append(apps, *a)
+	}
+	sort.Slice(apps, func(i, j int) bool {
+		a, _ := strconv.Atoi(apps[i].ID)
+		b, _ := strconv.Atoi(apps[j].ID)
+		return a < b
+	})
+	return apps
+}
+
+func (s *Server) logf(format string, args ...interface{}) {
+	if s.opts.Logf != nil {
+		s.opts.Logf(format, args...)
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+
+	// 管理用エンドポイントは認証・失敗注入の対象外
+	if strings.HasPrefix(path, "/__mock/") {
+		s.serveAdmin(w, r)
+		return
+	}
+
+	if f := s.takeFailure(path); f != nil {
+		s.logf("%s %s -> %d（注入した失敗）", r.Method, path, f.Status)
+		writeError(w, f.Status, f.Code, f.Message)
+		return
+	}
+
+	if path != "/" && path != "/k/" && !s.authorized(r) {
+		s.logf("%s %s -> 401", r.Method, path)
+		writeError(w, http.StatusUnauthorized, "CB_WA01", "ユーザーのパスワード認証に失敗しました。")
+		return
+	}
+
+	switch {
+	case path == "/" || path == "/k/":
+		s.serveIndex(w, r)
+	case path == "/k/v1/file.json" && r.Method == http.MethodPost:
+		s.serveUpload(w, r)
+	case path == "/k/v1/file.json" && r.Method == http.MethodGet:
+		s.serveDownload(w, r)
+	case path == "/k/api/dev/plugin/import.json" && r.Method == http.MethodPost:
+		s.serveImport(w, r)
+	case path == "/k/v1/plugins.json" && r.Method == http.MethodGet:
+		s.servePlugins(w, r)
+	case path == "/k/v1/plugin/apps.json" && r.Method == http.MethodGet:
+		s.servePluginApps(w, r)
+	case path == "/k/v1/apps.json" && r.Method == http.MethodGet:
+		s.serveAppsInfo(w, r)
+	case path == "/k/v1/preview/app.json" && r.Method == http.MethodPost:
+		s.serveCreateApp(w, r)
+	case path == "/k/v1/app/form/fields.json":
+		s.serveFormFields(w, r, false)
+	case path == "/k/v1/preview/app/form/fields.json":
+		s.serveFormFields(w, r, true)
+	case path == "/k/v1/records.json":
+		s.serveRecords(w, r)
+	case path == "/k/v1/records/cursor.json":
+		s.serveRecordCursor(w, r)
+	case path == "/k/v1/app/customize.json":
+		s.serveAppCustomize(w, r, false)
+	case path == "/k/v1/preview/app/customize.json":
+		s.serveAppCustomize(w, r, true)
+	case path == "/k/v1/preview/app/plugins.json":
+		s.serveAppPlugins(w, r)
+	case path == "/k/v1/preview/app/deploy.json":
+		s.serveAppDeploy(w, r)
+	default:
+		s.logf("%s %s -> 404", r.Method, path)
+		writeError(w, http.StatusNotFound, "GAIA_NO01", "指定したAPIは存在しません。")
+	}
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if s.opts.Username == "" && s.opts.Password == "" {
+		return true
+	}
+	want := base64.StdEncoding.EncodeToString([]byte(s.opts.Username + ":" + s.opts.Password))
+	return r.Header.Get("X-Cybozu-Authorization") == want
+}
+
+// POST /k/v1/file.json
+func (s *Server) serveUpload(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "CB_VA01", "file を指定してください。")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "CB_VA01", "ファイルを読み込めません。")
+		return
+	}
+
+	s.mu.Lock()
+	s.nextFile++
+	fileKey := fmt.Sprintf("mock-file-%d", s.nextFile)
+	s.files[fileKey] = data
+	s.fileNames[fileKey] = header.Filename
+	s.mu.Unlock()
+
+	s.logf("POST /k/v1/file.json %s (%d bytes) -> %s", header.Filename, len(data), fileKey)
+	writeJSON(w, http.StatusOK, map[string]string{"fileKey": fileKey})
+}
+
+// POST /k/api/dev/plugin/import.json
+func (s *Server) serveImport(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Item string `json:"item"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Item == "" {
+		writeError(w, http.StatusBadRequest, "CB_VA01", "item を指定してください。")
+		return
+	}
+
+	s.mu.Lock()
+	data, ok := s.files[body.Item]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusBadRequest, "GAIA_BL01", "指定したファイル（"+body.Item+"）が見つかりません。")
+		return
+	}
+
+	info, err := VerifyPluginZip(data)
+	if err != nil {
+		s.logf("POST /k/api/dev/plugin/import.json -> 400 %v", err)
+		writeError(w, http.StatusBadRequest, "GAIA_PL03", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	p, exists := s.plugins[info.ID]
+	if !exists {
+		p = &Plugin{ID: info.ID}
+		s.plugins[info.ID] = p
+	}
+	p.Name = info.Name
+	p.Description = info.Description
+	p.Version = info.Version
+	p.Revision++
+	p.UpdatedAt = time.Now()
+	revision := p.Revision
+	s.mu.Unlock()
+
+	action := "インストール"
+	if exists {
+		action = "更新"
+	}
+	s.logf("POST /k/api/dev/plugin/import.json -> %s %s v%s（%s, revision %d）", info.ID, info.Name, info.Version, action, revision)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"result": map[string]interface{}{
+			"pluginId": info.ID,
+			"version":  revision,
+		},
+	})
+}
+
+// GET /k/v1/plugins.json
+func (s *Server) servePlugins(w http.ResponseWriter, r *http.Request) {
+	type pluginJSON struct {
+		ID             string `json:"id"`
+		Name           string `json:"name"`
+		Description    string `json:"description"`
+		Version        string `json:"version"`
+		IsMarketPlugin bool   `json:"isMarketPlugin"`
+	}
+
+	plugins := s.Plugins()
+	list := make([]pluginJSON, len(plugins))
+	for i, p := range plugins {
+		list[i] = pluginJSON{ID: p.ID, Name: p.Name, Description: p.Description, Version: p.Version}
+	}
+
+	s.logf("GET /k/v1/plugins.json -> %d 件", len(list))
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"plugins":    list,
+		"totalCount": len(list),
+	})
+}
+
+// GET / （インストール済みプラグインの一覧）
+func (s *Server) serveIndex(w http.ResponseWriter, r *http.Request) {
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html><html lang=\"ja\"><head><meta charset=\"UTF-8\"><title>kpdev mock-server</title></head><body>")
+	sb.WriteString("<h1>kpdev mock-server</h1><h2>インストール済みプラグイン</h2><ul>")
+	for _, p := range s.Plugins() {
+		fmt.Fprintf(&sb, "<li><code>%s</code> %s v%s（revision %d）</li>",
+			htmlEscape(p.ID), htmlEscape(p.Name), htmlEscape(p.Version), p.Revision)
+	}
+	sb.WriteString("</ul></body></html>")
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	io.WriteString(w, sb.String())
+}
+
+// /__mock/* は CI などからモックの状態を操作するためのエンドポイント
+func (s *Server) serveAdmin(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/__mock/state" && r.Method == http.MethodGet:
+		s.mu.Lock()
+		failures := make([]Failure, len(s.failures))
+		for i, f := range s.failures {
+			failures[i] = *f
+		}
+		files := len(s.files)
+		s.mu.Unlock()
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"plugins":  s.Plugins(),
+			"apps":     s.Apps(),
+			"failures": failures,
+			"files":    files,
+		})
+
+	case r.URL.Path == "/__mock/failures" && r.Method == http.MethodPost:
+		var f Failure
+		if err := json.NewDecoder(r.Body).Decode(&f); err != nil || f.Path == "" {
+			writeError(w, http.StatusBadRequest, "KPDEV_MOCK", "path を指定してください")
+			return
+		}
+		s.InjectFailure(f)
+		writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+
+	case r.URL.Path == "/__mock/failures" && r.Method == http.MethodDelete:
+		s.ClearFailures()
+		writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+
+	case r.URL.Path == "/__mock/reset" && r.Method == http.MethodPost:
+		s.Reset()
+		s.logf("状態をリセットしました")
+		writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+
+	default:
+		writeError(w, http.StatusNotFound, "KPDEV_MOCK", "不明な管理用エンドポイントです")
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError は kintone と同じ形式のエラーレスポンスを返す
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]string{
+		"code":    code,
+		"id":      fmt.Sprintf("mock-%d", time.Now().UnixNano()),
+		"message": message,
+	})
+}
+
+func htmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\"", "&quot;").Replace(s)
+}
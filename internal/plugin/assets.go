@@ -0,0 +1,89 @@
+package plugin
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// assetsDirNames はアセットのディレクトリ（先に見つかった方を使う）
+var assetsDirNames = []string{"assets", "public"}
+
+// AssetFile は dist/assets/ にコピーしたアセット
+type AssetFile struct {
+	// Path はアセットのディレクトリからの相対パス（build.assetsBaseUrl からのパスと同じ）
+	Path string
+	Size int64
+}
+
+// AssetsOutDir はビルドしたアセットの出力先（build.assetsBaseUrl に配置する。プラグインZIPには含めない）
+func AssetsOutDir(distDir string) string {
+	return filepath.Join(distDir, "assets")
+}
+
+// AssetsDir はプロジェクトのアセットのディレクトリを返す（assets/ がなければ public/、どちらもなければ空）
+func AssetsDir(projectDir string) string {
+	for _, name := range assetsDirNames {
+		dir := filepath.Join(projectDir, name)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	return ""
+}
+
+// collectAssets はアセットのディレクトリ以下のファイルを返す（. で始まるファイル・ディレクトリは除く）
+func collectAssets(projectDir string) ([]AssetFile, error) {
+	dir := AssetsDir(projectDir)
+	if dir == "" {
+		return nil, nil
+	}
+
+	var assets []AssetFile
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return fmt.Errorf("アセットに通常のファイル以外は含められません: %s", path)
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		assets = append(assets, AssetFile{Path: filepath.ToSlash(rel), Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("アセットの読み込みエラー: %w", err)
+	}
+	return assets, nil
+}
+
+// copyAssets はアセットを dstDir に同じパスでコピーする
+func copyAssets(projectDir, dstDir string, assets []AssetFile) error {
+	dir := AssetsDir(projectDir)
+	for _, asset := range assets {
+		rel := filepath.FromSlash(asset.Path)
+		dst := filepath.Join(dstDir, rel)
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		if err := copyFile(filepath.Join(dir, rel), dst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
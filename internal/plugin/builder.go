@@ -3,139 +3,426 @@ package plugin
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"maps"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/kintone/kpdev/internal/config"
 	"github.com/kintone/kpdev/internal/generator"
 )
 
 type BuildOptions struct {
-	Mode          string // "prod" or "pre"
-	Minify        bool
-	RemoveConsole bool
+	// Profile はビルドプロファイル（minify・console削除・名前の接頭辞・署名鍵など）
+	Profile *config.ResolvedProfile
+	// Externals はバンドルせず CDN から読み込むパッケージ（nil なら config.json の build.externals）
+	Externals []string
+	// Log を指定すると vite build の出力をバンドル名付きで逐次書き込む（nil なら失敗時のみエラーに含める）
+	Log io.Writer
+	// NoCache を指定するとビルドキャッシュを使わない（保存もしない）
+	NoCache bool
+	// Env は本番環境の定数（import.meta.env.{キー} として埋め込む）
+	Env map[string]interface{}
+	// Environment を指定すると ZIP のファイル名の末尾に付ける（環境ごとに定数が異なる場合）
+	Environment string
+	// KeepZips を指定すると dist/ にある ZIP を削除しない（環境・バリアントごとに続けてビルドする場合）
+	KeepZips bool
+	// Variant を指定すると manifest・アイコン・署名鍵・定数をバリアントのものにする
+	Variant *config.Variant
+}
+
+// BuildStep はビルドの工程と所要時間
+type BuildStep struct {
+	Name     string
+	Duration time.Duration
+	// Sub は工程の内訳（Vite ビルドではバンドルごとの所要時間。並列に実行するため合計は工程より長くなる）
+	Sub []BuildStep
+	// Cached はキャッシュを再利用してビルドを省略したかどうか
+	Cached bool
+}
+
+// BuildResult はビルドの結果
+type BuildResult struct {
+	ZipPath string
+	// PluginID は署名した秘密鍵から求めたプラグインID
+	PluginID string
+	// Assets は dist/assets/ にコピーしたファイル（build.assetsBaseUrl に配置する）
+	Assets []AssetFile
+	// Sizes は出力ごとのサイズと前回のビルドからの増減・build.budgets の上限
+	Sizes []OutputSize
+	// Previous は差分の比較に使った前回のビルドの記録（なければ nil）
+	Previous *config.BuildSizeRecord
+	// Steps は Build の工程ごとの所要時間
+	Steps []BuildStep
+}
+
+// Total はビルド全体の所要時間を返す
+func (r *BuildResult) Total() time.Duration {
+	var total time.Duration
+	for _, s := range r.Steps {
+		total += s.Duration
+	}
+	return total
+}
+
+// step は工程を実行して所要時間を記録する
+func (r *BuildResult) step(name string, fn func() ([]BuildStep, error)) error {
+	start := time.Now()
+	sub, err := fn()
+	r.Steps = append(r.Steps, BuildStep{Name: name, Duration: time.Since(start), Sub: sub})
+	return err
+}
+
+// noSub は内訳のない工程を step に渡す
+func noSub(fn func() error) func() ([]BuildStep, error) {
+	return func() ([]BuildStep, error) {
+		return nil, fn()
+	}
 }
 
 // Build は本番用プラグインをビルドする
-func Build(projectDir string, opts *BuildOptions) (string, error) {
+func Build(projectDir string, opts *BuildOptions) (*BuildResult, error) {
 	distDir := filepath.Join(projectDir, "dist")
 	pluginDir := filepath.Join(distDir, "plugin")
+	result := &BuildResult{}
+
+	var (
+		cfg       *config.Config
+		resources []string
+		externals []ExternalLibrary
+		parallel  bool
+		cacheKeys map[string]string
+		defines   map[string]string
+		budgets   map[string]config.SizeLimits
+		version   string
+	)
+	err := result.step("準備", noSub(func() error {
+		// dist/ をクリーン
+		if err := cleanDistDir(distDir, opts.KeepZips); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(pluginDir, 0755); err != nil {
+			return err
+		}
 
-	// dist/ をクリーン
-	if err := os.RemoveAll(distDir); err != nil {
-		return "", err
-	}
-	if err := os.MkdirAll(pluginDir, 0755); err != nil {
-		return "", err
-	}
+		// 設定を読み込み
+		var err error
+		cfg, err = config.Load(projectDir)
+		if err != nil {
+			return fmt.Errorf("設定読み込みエラー: %w", err)
+		}
+
+		if err := cfg.CheckEntries(projectDir); err != nil {
+			return err
+		}
+
+		// manifest.json に追加したリソースを確認（ビルド前に失敗させる）
+		resources, err = prodLocalResources(projectDir, cfg)
+		if err != nil {
+			return err
+		}
+
+		// サイズの上限を確認（ビルド前に失敗させる）
+		budgets, err = cfg.Budgets()
+		if err != nil {
+			return err
+		}
+
+		// assets/（public/）のファイルを確認
+		// kintone はプラグインZIP内のファイルを URL で配信しないため、アセットは build.assetsBaseUrl に置く
+		result.Assets, err = collectAssets(projectDir)
+		if err != nil {
+			return err
+		}
+		if len(result.Assets) > 0 {
+			base := cfg.AssetsBaseURL()
+			if base == "" {
+				return fmt.Errorf("%s/ のファイル（%d件）を使うには .kpdev/config.json の build.assetsBaseUrl にアセットを配置する URL を指定してください（kintone はプラグインZIP内のファイルを URL で配信しません）", filepath.Base(AssetsDir(projectDir)), len(result.Assets))
+			}
+			if !strings.HasPrefix(base, "https://") {
+				return fmt.Errorf("build.assetsBaseUrl は https:// で始まる URL を指定してください: %s", base)
+			}
+		}
+
+		// 外部化するライブラリを CDN の URL に解決
+		externalNames := opts.Externals
+		if externalNames == nil && cfg.Build != nil {
+			externalNames = cfg.Build.Externals
+		}
+		externals, err = ResolveExternals(projectDir, externalNames)
+		if err != nil {
+			return err
+		}
+
+		// 最新の vite.config.ts のみバンドルごとの出力先を指定して並列にビルドできる
+		data, err := os.ReadFile(filepath.Join(config.GetConfigDir(projectDir), "vite.config.ts"))
+		if err != nil {
+			return fmt.Errorf("vite.config.ts の読み込みエラー: %w", err)
+		}
+		parallel = !generator.IsViteConfigOutdated(string(data))
+		if len(externals) > 0 && !parallel {
+			return fmt.Errorf("vite.config.ts が古い形式のため外部化できません。kpdev migrate で更新してください")
+		}
+		if (opts.Profile.Sourcemap != "" || len(opts.Profile.Define) > 0) && !parallel {
+			return fmt.Errorf("vite.config.ts が古い形式のためビルドプロファイル %s の sourcemap / define を使えません。kpdev migrate で更新してください", opts.Profile.Name)
+		}
+		if len(opts.Env) > 0 && !parallel {
+			return fmt.Errorf("vite.config.ts が古い形式のため環境の定数を埋め込めません。kpdev migrate で更新してください")
+		}
+		if opts.Variant != nil && len(opts.Variant.Define) > 0 && !parallel {
+			return fmt.Errorf("vite.config.ts が古い形式のためバリアント %s の define を使えません。kpdev migrate で更新してください", opts.Variant.Name)
+		}
+		defines, err = config.DefineJSON(opts.Profile.Define)
+		if err != nil {
+			return err
+		}
+		if opts.Variant != nil {
+			variantDefines, err := config.DefineJSON(opts.Variant.Define)
+			if err != nil {
+				return err
+			}
+			maps.Copy(defines, variantDefines)
+
+			// バリアントの鍵がなければ生成し、アイコンを確認する（ビルド前に失敗させる）
+			if opts.Variant.Key == "" {
+				if _, err := generator.EnsureKeyFile(opts.Variant.KeyPath(projectDir)); err != nil {
+					return fmt.Errorf("バリアント %s の秘密鍵の生成エラー: %w", opts.Variant.Name, err)
+				}
+			}
+			if opts.Variant.Icon != "" {
+				if _, err := os.Stat(filepath.Join(projectDir, filepath.FromSlash(opts.Variant.Icon))); err != nil {
+					return fmt.Errorf("バリアント %s のアイコンが見つかりません: %s", opts.Variant.Name, opts.Variant.Icon)
+				}
+			}
+		}
+		envDefines, err := config.EnvDefines(opts.Env)
+		if err != nil {
+			return err
+		}
+		maps.Copy(defines, envDefines)
 
-	// 設定を読み込み
-	cfg, err := config.Load(projectDir)
+		// ソースとビルドの設定からバンドルごとのキャッシュキーを計算
+		if !opts.NoCache {
+			srcHash, err := sourceHash(projectDir, cfg)
+			if err != nil {
+				return fmt.Errorf("キャッシュキーの計算エラー: %w", err)
+			}
+			cacheKeys = map[string]string{}
+			for bundle := range cfg.Bundles() {
+				cacheKeys[bundle] = bundleCacheKey(srcHash, bundleCacheInputs{
+					Bundle:        bundle,
+					Entries:       cfg.Bundles(),
+					Minify:        opts.Profile.Minify,
+					RemoveConsole: opts.Profile.RemoveConsole,
+					Sourcemap:     opts.Profile.Sourcemap,
+					Define:        defines,
+					Externals:     ExternalGlobals(externals),
+					AssetsBaseURL: cfg.AssetsBaseURL(),
+				})
+			}
+		}
+		return nil
+	}))
 	if err != nil {
-		return "", fmt.Errorf("設定読み込みエラー: %w", err)
+		return nil, err
 	}
 
 	// Vite でビルド
-	if err := runViteBuild(projectDir, opts); err != nil {
-		return "", fmt.Errorf("Viteビルドエラー: %w", err)
+	err = result.step("Vite ビルド", func() ([]BuildStep, error) {
+		bundles, err := runViteBuild(projectDir, cfg, opts, externals, defines, parallel, cacheKeys)
+		if err != nil {
+			return nil, fmt.Errorf("Viteビルドエラー: %w", err)
+		}
+		return bundles, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// ビルド成果物を整理
-	if err := organizeDistFiles(projectDir, pluginDir, cfg); err != nil {
-		return "", fmt.Errorf("ファイル整理エラー: %w", err)
-	}
+	err = result.step("ファイル整理", noSub(func() error {
+		// ビルド成果物を整理
+		if err := organizeDistFiles(projectDir, pluginDir, cfg); err != nil {
+			return fmt.Errorf("ファイル整理エラー: %w", err)
+		}
 
-	// 一時ビルドファイルを削除
-	cleanupTempFiles(distDir)
+		// hidden のソースマップは ZIP に含めず dist/sourcemaps/ に置く
+		if opts.Profile.Sourcemap == "hidden" {
+			if err := organizeSourcemaps(distDir, cfg); err != nil {
+				return fmt.Errorf("ソースマップの整理エラー: %w", err)
+			}
+		}
 
-	// manifest.json を生成
-	if err := generateProdManifest(projectDir, pluginDir, cfg, opts); err != nil {
-		return "", fmt.Errorf("manifest生成エラー: %w", err)
-	}
+		// 一時ビルドファイルを削除
+		cleanupTempFiles(distDir)
 
-	// icon.png をコピー（存在しない場合は生成）
-	srcIcon := filepath.Join(projectDir, "icon.png")
-	if _, err := os.Stat(srcIcon); os.IsNotExist(err) {
-		if err := generator.GenerateIcon(projectDir); err != nil {
-			return "", fmt.Errorf("アイコン生成エラー: %w", err)
+		// assets/（public/）を dist/assets/ に同じパスでコピー（プラグインZIPには含めない）
+		if err := copyAssets(projectDir, AssetsOutDir(distDir), result.Assets); err != nil {
+			return fmt.Errorf("アセットのコピーエラー: %w", err)
 		}
-	}
-	dstIcon := filepath.Join(pluginDir, "icon.png")
-	if err := copyFile(srcIcon, dstIcon); err != nil {
-		return "", fmt.Errorf("iconコピーエラー: %w", err)
-	}
 
-	// config.html を生成
-	if err := generateProdConfigHTML(projectDir, pluginDir); err != nil {
-		return "", fmt.Errorf("config.html生成エラー: %w", err)
+		// manifest.json に追加したローカルファイルを同じパスでコピー
+		for _, res := range resources {
+			dst := filepath.Join(pluginDir, filepath.FromSlash(res))
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				return err
+			}
+			if err := copyFile(filepath.Join(projectDir, filepath.FromSlash(res)), dst); err != nil {
+				return fmt.Errorf("リソースのコピーエラー: %w", err)
+			}
+		}
+		return nil
+	}))
+	if err != nil {
+		return nil, err
 	}
 
-	// LICENSEファイルをコピー（存在する場合）
-	if err := copyLicenseIfExists(projectDir, pluginDir); err != nil {
-		return "", fmt.Errorf("LICENSEファイルのコピーエラー: %w", err)
-	}
+	err = result.step("manifest・設定画面の生成", noSub(func() error {
+		// manifest.json を生成
+		if err := generateProdManifest(projectDir, pluginDir, cfg, opts, externals); err != nil {
+			return fmt.Errorf("manifest生成エラー: %w", err)
+		}
 
-	// プラグインZIPを作成（署名付き）
-	version := getManifestVersion(projectDir)
-	nameEn := getManifestNameEn(projectDir)
-	safeName := sanitizeFilename(nameEn)
-	modeLabel := "prod"
-	if opts.Mode == "pre" {
-		modeLabel = "pre"
-	}
-	zipPath := filepath.Join(distDir, fmt.Sprintf("%s-%s-v%s.zip", safeName, modeLabel, version))
+		// icon.png をコピー（存在しない場合は生成。バリアントのアイコンがあればそれを使う）
+		srcIcon := filepath.Join(projectDir, "icon.png")
+		if opts.Variant != nil && opts.Variant.Icon != "" {
+			srcIcon = filepath.Join(projectDir, filepath.FromSlash(opts.Variant.Icon))
+		}
+		if _, err := os.Stat(srcIcon); os.IsNotExist(err) {
+			if err := generator.GenerateIcon(projectDir); err != nil {
+				return fmt.Errorf("アイコン生成エラー: %w", err)
+			}
+		}
+		dstIcon := filepath.Join(pluginDir, "icon.png")
+		if err := copyFile(srcIcon, dstIcon); err != nil {
+			return fmt.Errorf("iconコピーエラー: %w", err)
+		}
 
-	var keyPath string
-	if opts.Mode == "pre" {
-		keyPath = generator.GetDevKeyPath(projectDir)
-	} else {
-		keyPath = generator.GetProdKeyPath(projectDir)
-	}
-	privateKey, err := generator.LoadPrivateKey(keyPath)
-	if err != nil {
-		return "", fmt.Errorf("秘密鍵読み込みエラー: %w", err)
-	}
+		// config.html を生成
+		if err := generateProdConfigHTML(projectDir, pluginDir); err != nil {
+			return fmt.Errorf("config.html生成エラー: %w", err)
+		}
 
-	if err := createPluginZip(pluginDir, zipPath, privateKey); err != nil {
-		return "", fmt.Errorf("ZIP作成エラー: %w", err)
+		// LICENSEファイルをコピー（存在する場合）
+		if err := copyLicenseIfExists(projectDir, pluginDir); err != nil {
+			return fmt.Errorf("LICENSEファイルのコピーエラー: %w", err)
+		}
+		return nil
+	}))
+	if err != nil {
+		return nil, err
 	}
 
-	return zipPath, nil
-}
+	err = result.step("ZIP作成・署名", noSub(func() error {
+		// プラグインZIPを作成（署名付き）
+		version = getManifestVersion(projectDir)
+		nameEn := getManifestNameEn(projectDir)
+		safeName := sanitizeFilename(nameEn)
+		zipName := opts.Profile.OutputName(safeName, version)
+		if opts.Variant != nil {
+			zipName = strings.TrimSuffix(zipName, ".zip") + "-" + opts.Variant.Name + ".zip"
+		}
+		if opts.Environment != "" {
+			// 環境名は日本語のこともあるため、ファイル名に使えない文字だけを置き換える
+			zipName = strings.TrimSuffix(zipName, ".zip") + "-" + envFilenamePattern.ReplaceAllString(opts.Environment, "_") + ".zip"
+		}
+		result.ZipPath = filepath.Join(distDir, zipName)
 
-func runViteBuild(projectDir string, opts *BuildOptions) error {
-	viteConfigPath := filepath.Join(config.GetConfigDir(projectDir), "vite.config.ts")
+		keyPath := ProfileKeyPath(projectDir, opts.Profile)
+		if opts.Variant != nil {
+			keyPath = opts.Variant.KeyPath(projectDir)
+		}
+		privateKey, err := generator.LoadPrivateKey(keyPath)
+		if err != nil {
+			return fmt.Errorf("秘密鍵読み込みエラー: %w", err)
+		}
+		result.PluginID, err = generator.GeneratePluginID(privateKey)
+		if err != nil {
+			return err
+		}
 
-	// main をビルド
-	if err := runSingleViteBuild(projectDir, viteConfigPath, "main", opts); err != nil {
-		return err
+		if err := createPluginZip(pluginDir, result.ZipPath, privateKey, nil, nil); err != nil {
+			return fmt.Errorf("ZIP作成エラー: %w", err)
+		}
+		return nil
+	}))
+	if err != nil {
+		return nil, err
 	}
 
-	// config をビルド
-	return runSingleViteBuild(projectDir, viteConfigPath, "config", opts)
-}
+	err = result.step("サイズ確認", noSub(func() error {
+		// kintone の上限を超える ZIP はアップロードできないため失敗させる
+		if err := CheckUploadLimits(result.ZipPath); err != nil {
+			return err
+		}
 
-func runSingleViteBuild(projectDir, viteConfigPath, entry string, opts *BuildOptions) error {
-	args := []string{"vite", "build", "--config", viteConfigPath}
+		var err error
+		result.Sizes, err = measureOutputs(pluginDir, result.ZipPath, budgets)
+		if err != nil {
+			return fmt.Errorf("サイズの計測エラー: %w", err)
+		}
+		if err := budgetError(result.Sizes); err != nil {
+			return err
+		}
 
-	if !opts.Minify {
-		args = append(args, "--minify", "false")
+		// 上限内なら記録する（失敗したビルドは次の差分の基準にしない）
+		variantName := ""
+		if opts.Variant != nil {
+			variantName = opts.Variant.Name
+		}
+		result.Previous, err = recordOutputSizes(projectDir, config.BuildSizesKey(opts.Profile.Name, variantName, opts.Environment), version, result.Sizes)
+		if err != nil {
+			return fmt.Errorf("%s の保存エラー: %w", config.BuildSizesFile, err)
+		}
+		return nil
+	}))
+	if err != nil {
+		return nil, err
 	}
 
-	cmd := exec.Command("npx", args...)
-	cmd.Dir = projectDir
-	cmd.Env = append(os.Environ(), "VITE_BUILD_ENTRY="+entry)
+	return result, nil
+}
 
-	output, err := cmd.CombinedOutput()
+// cleanDistDir は dist/ を削除して作り直す（keepZips なら直下の ZIP は残す）
+func cleanDistDir(distDir string, keepZips bool) error {
+	if !keepZips {
+		return os.RemoveAll(distDir)
+	}
+	entries, err := os.ReadDir(distDir)
 	if err != nil {
-		return fmt.Errorf("%w\n%s", err, string(output))
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, e := range entries {
+		if !e.IsDir() && filepath.Ext(e.Name()) == ".zip" {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(distDir, e.Name())); err != nil {
+			return err
+		}
 	}
-
 	return nil
 }
 
+// ProfileKeyPath はビルドプロファイルで署名に使う秘密鍵のパスを返す
+func ProfileKeyPath(projectDir string, p *config.ResolvedProfile) string {
+	switch p.Key {
+	case "", "prod":
+		return generator.GetProdKeyPath(projectDir)
+	case "dev":
+		return generator.GetDevKeyPath(projectDir)
+	}
+	if filepath.IsAbs(p.Key) {
+		return p.Key
+	}
+	return filepath.Join(projectDir, filepath.FromSlash(p.Key))
+}
+
 func organizeDistFiles(projectDir, pluginDir string, cfg *config.Config) error {
 	distDir := filepath.Join(projectDir, "dist")
 
@@ -157,23 +444,29 @@ func organizeDistFiles(projectDir, pluginDir string, cfg *config.Config) error {
 		return err
 	}
 
-	// main.js → desktop.js, mobile.js にコピー
-	mainJS := filepath.Join(distDir, "main.js")
-	if _, err := os.Stat(mainJS); err == nil {
-		if cfg.Targets.Desktop {
-			if err := copyFile(mainJS, filepath.Join(jsDir, "desktop.js")); err != nil {
+	// 対象ごとのバンドル（個別のエントリーがなければ main）→ desktop.js, mobile.js にコピー
+	targets := map[string]bool{"desktop": cfg.Targets.Desktop, "mobile": cfg.Targets.Mobile}
+	for _, target := range []string{"desktop", "mobile"} {
+		if !targets[target] {
+			continue
+		}
+		bundle := cfg.TargetBundle(target)
+		bundleJS := filepath.Join(bundleOutDir(distDir, bundle), bundle+".js")
+		if _, err := os.Stat(bundleJS); err == nil {
+			if err := copyFile(bundleJS, filepath.Join(jsDir, target+".js")); err != nil {
 				return err
 			}
 		}
-		if cfg.Targets.Mobile {
-			if err := copyFile(mainJS, filepath.Join(jsDir, "mobile.js")); err != nil {
+		bundleCSS := filepath.Join(bundleOutDir(distDir, bundle), bundle+".css")
+		if _, err := os.Stat(bundleCSS); err == nil {
+			if err := copyFile(bundleCSS, filepath.Join(cssDir, target+".css")); err != nil {
 				return err
 			}
 		}
 	}
 
 	// config.js をコピー
-	configJS := filepath.Join(distDir, "config.js")
+	configJS := filepath.Join(bundleOutDir(distDir, "config"), "config.js")
 	if _, err := os.Stat(configJS); err == nil {
 		if err := copyFile(configJS, filepath.Join(jsDir, "config.js")); err != nil {
 			return err
@@ -181,21 +474,7 @@ func organizeDistFiles(projectDir, pluginDir string, cfg *config.Config) error {
 	}
 
 	// CSS ファイルをコピー（存在する場合）
-	mainCSS := filepath.Join(distDir, "main.css")
-	if _, err := os.Stat(mainCSS); err == nil {
-		if cfg.Targets.Desktop {
-			if err := copyFile(mainCSS, filepath.Join(cssDir, "desktop.css")); err != nil {
-				return err
-			}
-		}
-		if cfg.Targets.Mobile {
-			if err := copyFile(mainCSS, filepath.Join(cssDir, "mobile.css")); err != nil {
-				return err
-			}
-		}
-	}
-
-	configCSS := filepath.Join(distDir, "config.css")
+	configCSS := filepath.Join(bundleOutDir(distDir, "config"), "config.css")
 	if _, err := os.Stat(configCSS); err == nil {
 		if err := copyFile(configCSS, filepath.Join(cssDir, "config.css")); err != nil {
 			return err
@@ -205,54 +484,63 @@ func organizeDistFiles(projectDir, pluginDir string, cfg *config.Config) error {
 	return nil
 }
 
-func generateProdManifest(projectDir, pluginDir string, cfg *config.Config, opts *BuildOptions) error {
-	// .kpdev/manifest.json を読み込み
-	srcManifest := filepath.Join(config.GetConfigDir(projectDir), "manifest.json")
-	data, err := os.ReadFile(srcManifest)
-	if err != nil {
+// organizeSourcemaps は hidden のソースマップを dist/sourcemaps/ に出力ファイルと同じ名前でコピーする
+func organizeSourcemaps(distDir string, cfg *config.Config) error {
+	sourcemapDir := filepath.Join(distDir, "sourcemaps")
+	if err := os.MkdirAll(sourcemapDir, 0755); err != nil {
 		return err
 	}
 
-	var manifest map[string]interface{}
-	if err := json.Unmarshal(data, &manifest); err != nil {
+	outputs := map[string]string{"config": "config"}
+	if cfg.Targets.Desktop {
+		outputs["desktop"] = cfg.TargetBundle("desktop")
+	}
+	if cfg.Targets.Mobile {
+		outputs["mobile"] = cfg.TargetBundle("mobile")
+	}
+	for output, bundle := range outputs {
+		src := filepath.Join(bundleOutDir(distDir, bundle), bundle+".js.map")
+		if _, err := os.Stat(src); err != nil {
+			continue
+		}
+		if err := copyFile(src, filepath.Join(sourcemapDir, output+".js.map")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func generateProdManifest(projectDir, pluginDir string, cfg *config.Config, opts *BuildOptions, externals []ExternalLibrary) error {
+	manifest, err := readSourceManifest(projectDir)
+	if err != nil {
 		return err
 	}
 
-	// preモードの場合、名前に[開発]を付与
-	if opts.Mode == "pre" {
-		if name, ok := manifest["name"].(map[string]interface{}); ok {
-			if ja, ok := name["ja"].(string); ok {
-				name["ja"] = "[開発] " + ja
-			}
-			if en, ok := name["en"].(string); ok {
-				name["en"] = "[DEV] " + en
-			}
-		}
+	// バリアントの名前・説明・URLを重ねる
+	if opts.Variant != nil {
+		opts.Variant.ApplyManifest(manifest)
 	}
 
-	// パスを更新
-	if cfg.Targets.Desktop {
-		manifest["desktop"] = map[string]interface{}{
-			"js": []string{"js/desktop.js"},
+	// プロファイルの接頭辞を名前に付与（pre は [開発]）
+	prefix := opts.Profile.NamePrefix
+	if name, ok := manifest["name"].(map[string]interface{}); ok {
+		if ja, ok := name["ja"].(string); ok && prefix.Ja != "" {
+			name["ja"] = prefix.Ja + ja
 		}
-		// CSS が存在する場合のみ追加
-		if _, err := os.Stat(filepath.Join(pluginDir, "css", "desktop.css")); err == nil {
-			desktop := manifest["desktop"].(map[string]interface{})
-			desktop["css"] = []string{"css/desktop.css"}
+		if en, ok := name["en"].(string); ok && prefix.En != "" {
+			name["en"] = prefix.En + en
 		}
+	}
+
+	// パスを更新（追加したリソースはバンドルの前後の指定した位置に読み込む）
+	if cfg.Targets.Desktop {
+		manifest["desktop"] = prodTargetResources(manifest, pluginDir, "desktop", externals)
 	} else {
 		delete(manifest, "desktop")
 	}
 
 	if cfg.Targets.Mobile {
-		manifest["mobile"] = map[string]interface{}{
-			"js": []string{"js/mobile.js"},
-		}
-		// CSS が存在する場合のみ追加
-		if _, err := os.Stat(filepath.Join(pluginDir, "css", "mobile.css")); err == nil {
-			mobile := manifest["mobile"].(map[string]interface{})
-			mobile["css"] = []string{"css/mobile.css"}
-		}
+		manifest["mobile"] = prodTargetResources(manifest, pluginDir, "mobile", externals)
 	} else {
 		delete(manifest, "mobile")
 	}
@@ -267,14 +555,8 @@ func generateProdManifest(projectDir, pluginDir string, cfg *config.Config, opts
 	}
 	delete(manifest, "required_params")
 
-	configMap := map[string]interface{}{
-		"html": "html/config.html",
-		"js":   []string{"js/config.js"},
-	}
-	// CSS が存在する場合のみ追加
-	if _, err := os.Stat(filepath.Join(pluginDir, "css", "config.css")); err == nil {
-		configMap["css"] = []string{"css/config.css"}
-	}
+	configMap := prodTargetResources(manifest, pluginDir, "config", externals)
+	configMap["html"] = "html/config.html"
 	// required_paramsをconfig内に復元
 	if existingRequiredParams != nil {
 		configMap["required_params"] = existingRequiredParams
@@ -287,6 +569,89 @@ func generateProdManifest(projectDir, pluginDir string, cfg *config.Config, opts
 	return os.WriteFile(filepath.Join(pluginDir, "manifest.json"), []byte(outData), 0644)
 }
 
+// prodTargetResources は desktop / mobile / config の js・css を
+// 外部化したライブラリ → 追加したリソース（前）→ バンドル → 追加したリソース（後）の順に並べる
+// CSS のバンドルはビルド結果に存在する場合のみ追加する
+func prodTargetResources(manifest map[string]interface{}, pluginDir, target string, externals []ExternalLibrary) map[string]interface{} {
+	result := map[string]interface{}{}
+	for _, kind := range []string{"js", "css"} {
+		before, after := config.ManifestResources(manifest, target, kind)
+		var entries []string
+		if kind == "js" {
+			// 同じURLを manifest.json に書いている場合は重複させない
+			for _, lib := range externals {
+				if !slices.Contains(before, lib.URL) && !slices.Contains(after, lib.URL) {
+					entries = append(entries, lib.URL)
+				}
+			}
+		}
+		entries = append(entries, before...)
+		bundle := config.BundleResourcePath(target, kind)
+		if _, err := os.Stat(filepath.Join(pluginDir, filepath.FromSlash(bundle))); err == nil || kind == "js" {
+			entries = append(entries, bundle)
+		}
+		entries = append(entries, after...)
+		if len(entries) > 0 {
+			result[kind] = entries
+		}
+	}
+	return result
+}
+
+// prodReservedPaths は本番用プラグインで kpdev が生成するファイル
+var prodReservedPaths = map[string]bool{
+	"manifest.json":    true,
+	"icon.png":         true,
+	"html/config.html": true,
+	"js/desktop.js":    true,
+	"js/mobile.js":     true,
+	"js/config.js":     true,
+	"css/desktop.css":  true,
+	"css/mobile.css":   true,
+	"css/config.css":   true,
+}
+
+// prodLocalResources は manifest.json に追加したリソースを検証し、ZIP にコピーするローカルファイルを返す
+func prodLocalResources(projectDir string, cfg *config.Config) ([]string, error) {
+	manifest, err := readSourceManifest(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("manifest.json の読み込みエラー: %w", err)
+	}
+
+	targets := []string{"config"}
+	if cfg.Targets.Desktop {
+		targets = append(targets, "desktop")
+	}
+	if cfg.Targets.Mobile {
+		targets = append(targets, "mobile")
+	}
+	for _, target := range targets {
+		for _, kind := range []string{"js", "css"} {
+			before, after := config.ManifestResources(manifest, target, kind)
+			for _, e := range append(before, after...) {
+				if err := config.ValidateManifestResource(projectDir, e, prodReservedPaths); err != nil {
+					return nil, fmt.Errorf("manifest.json の %s.%s: %w", target, kind, err)
+				}
+			}
+		}
+	}
+	return config.ManifestLocalResources(manifest, targets), nil
+}
+
+// readSourceManifest は .kpdev/manifest.json を読み込む
+func readSourceManifest(projectDir string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(filepath.Join(config.GetConfigDir(projectDir), "manifest.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest map[string]interface{}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
 func generateProdConfigHTML(projectDir, pluginDir string) error {
 	htmlDir := filepath.Join(pluginDir, "html")
 	if err := os.MkdirAll(htmlDir, 0755); err != nil {
@@ -335,17 +700,7 @@ func copyFile(src, dst string) error {
 
 func cleanupTempFiles(distDir string) {
 	// Vite が出力した一時ファイルを削除
-	tempFiles := []string{
-		"main.js",
-		"main.css",
-		"config.js",
-		"config.css",
-	}
-
-	for _, file := range tempFiles {
-		path := filepath.Join(distDir, file)
-		os.Remove(path) // エラーは無視（存在しない場合がある）
-	}
+	os.RemoveAll(filepath.Join(distDir, viteOutDir)) // エラーは無視（存在しない場合がある）
 }
 
 // getManifestNameEn は manifest.json から英語名を取得する
@@ -386,6 +741,9 @@ func copyLicenseIfExists(projectDir, destDir string) error {
 	return nil
 }
 
+// envFilenamePattern は ZIP のファイル名に付ける環境名で置き換える文字
+var envFilenamePattern = regexp.MustCompile(`[^\p{L}\p{N}._-]+`)
+
 // sanitizeFilename は英数字以外をアンダースコアに変換する
 func sanitizeFilename(name string) string {
 	// 英数字以外をアンダースコアに置換
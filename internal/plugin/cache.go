@@ -0,0 +1,299 @@
+package plugin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kintone/kpdev/internal/config"
+)
+
+// cacheBundlesDir はバンドルのキャッシュを保存するディレクトリ（.kpdev/cache/ からの相対）
+const cacheBundlesDir = "bundles"
+
+// cacheKeepPerBundle はバンドルごとに残すキャッシュの数（古いものから削除する）
+const cacheKeepPerBundle = 5
+
+// cacheMetaFile はキャッシュエントリのメタデータ
+const cacheMetaFile = "cache.json"
+
+// GetCacheDir はビルドキャッシュのディレクトリ（.kpdev/cache）を返す
+func GetCacheDir(projectDir string) string {
+	return filepath.Join(config.GetConfigDir(projectDir), "cache")
+}
+
+// BundleCacheEntry はキャッシュしたバンドル
+type BundleCacheEntry struct {
+	Key       string    `json:"-"`
+	Bundle    string    `json:"bundle"`
+	CreatedAt time.Time `json:"createdAt"`
+	UsedAt    time.Time `json:"usedAt"`
+	Size      int64     `json:"-"`
+}
+
+// bundleCacheInputs はソース以外でバンドルの内容に影響する値
+// フィールドを追加するとキャッシュキーが変わる
+type bundleCacheInputs struct {
+	Bundle        string            `json:"bundle"`
+	Entries       map[string]string `json:"entries"`
+	Minify        bool              `json:"minify"`
+	RemoveConsole bool              `json:"removeConsole"`
+	Sourcemap     string            `json:"sourcemap,omitempty"`
+	Define        map[string]string `json:"define,omitempty"`
+	Externals     map[string]string `json:"externals,omitempty"`
+	AssetsBaseURL string            `json:"assetsBaseUrl,omitempty"`
+}
+
+// cacheRootFiles はプロジェクトルートのうち、バンドルの内容に影響するファイル
+// （ロックファイル・TypeScript / PostCSS / Tailwind などの設定・Vite が読み込む .env）
+var cacheRootFiles = []string{
+	"package-lock.json", "yarn.lock", "pnpm-lock.yaml", "bun.lockb", "bun.lock",
+	"tsconfig*.json", "*.config.js", "*.config.cjs", "*.config.mjs", "*.config.ts",
+	".env", ".env.*",
+}
+
+// sourceHash はバンドルに影響するファイルの内容をまとめたハッシュ
+// バンドル間で共通のため、Build ごとに1回だけ計算する
+func sourceHash(projectDir string, cfg *config.Config) (string, error) {
+	files := map[string]bool{}
+
+	// src/ 以下のすべてのファイル
+	srcDir := filepath.Join(projectDir, "src")
+	err := filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == srcDir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.IsDir() {
+			files[path] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// src/ の外にあるエントリー
+	for _, entry := range cfg.Bundles() {
+		files[filepath.Join(projectDir, filepath.FromSlash(strings.TrimPrefix(entry, "/")))] = true
+	}
+
+	for _, pattern := range cacheRootFiles {
+		matches, _ := filepath.Glob(filepath.Join(projectDir, pattern))
+		for _, m := range matches {
+			files[m] = true
+		}
+	}
+	files[filepath.Join(config.GetConfigDir(projectDir), "vite.config.ts")] = true
+
+	h := sha256.New()
+	for _, path := range slices.Sorted(maps.Keys(files)) {
+		rel, _ := filepath.Rel(projectDir, path)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", filepath.ToSlash(rel), len(data))
+		h.Write(data)
+	}
+
+	// package.json は version 以外（kpdev build でバージョンだけ変えた場合はキャッシュを使う）
+	if data, err := os.ReadFile(filepath.Join(projectDir, "package.json")); err == nil {
+		var pkg map[string]interface{}
+		if err := json.Unmarshal(data, &pkg); err != nil {
+			return "", fmt.Errorf("package.json の解析エラー: %w", err)
+		}
+		delete(pkg, "version")
+		normalized, _ := json.Marshal(pkg)
+		fmt.Fprintf(h, "package.json\x00%d\x00", len(normalized))
+		h.Write(normalized)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// bundleCacheKey はソースのハッシュとビルドの設定からバンドルのキャッシュキーを返す
+func bundleCacheKey(srcHash string, inputs bundleCacheInputs) string {
+	data, _ := json.Marshal(inputs)
+	h := sha256.New()
+	h.Write([]byte(srcHash))
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func bundleCachePath(projectDir, key string) string {
+	return filepath.Join(GetCacheDir(projectDir), cacheBundlesDir, key)
+}
+
+// restoreBundleCache はキャッシュしたバンドルを outDir にコピーする
+// キャッシュがなければ false を返す
+func restoreBundleCache(projectDir, key, bundle, outDir string) (bool, error) {
+	dir := bundleCachePath(projectDir, key)
+	meta, err := readCacheMeta(dir)
+	if err != nil {
+		return false, nil
+	}
+
+	if err := copyBundleFiles(dir, outDir, bundle); err != nil {
+		return false, err
+	}
+
+	meta.UsedAt = time.Now()
+	return true, writeCacheMeta(dir, meta)
+}
+
+// storeBundleCache はビルドしたバンドルをキャッシュに保存する
+// 途中で失敗しても壊れたキャッシュが残らないよう、一時ディレクトリに書いてから移動する
+func storeBundleCache(projectDir, key, bundle, outDir string) error {
+	dir := bundleCachePath(projectDir, key)
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := copyBundleFiles(outDir, tmp, bundle); err != nil {
+		return err
+	}
+	now := time.Now()
+	if err := writeCacheMeta(tmp, &BundleCacheEntry{Bundle: bundle, CreatedAt: now, UsedAt: now}); err != nil {
+		return err
+	}
+
+	os.RemoveAll(dir)
+	if err := os.Rename(tmp, dir); err != nil {
+		return err
+	}
+	return pruneBundleCache(projectDir, bundle)
+}
+
+// pruneBundleCache はバンドルごとに最近使った cacheKeepPerBundle 個を残して削除する
+func pruneBundleCache(projectDir, bundle string) error {
+	entries, err := ListBundleCache(projectDir)
+	if err != nil {
+		return err
+	}
+
+	kept := 0
+	for _, e := range entries {
+		if e.Bundle != bundle {
+			continue
+		}
+		if kept < cacheKeepPerBundle {
+			kept++
+			continue
+		}
+		if err := os.RemoveAll(bundleCachePath(projectDir, e.Key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListBundleCache はキャッシュしたバンドルを最近使った順に返す
+func ListBundleCache(projectDir string) ([]BundleCacheEntry, error) {
+	root := filepath.Join(GetCacheDir(projectDir), cacheBundlesDir)
+	dirs, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []BundleCacheEntry
+	for _, d := range dirs {
+		if !d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+			continue
+		}
+		dir := filepath.Join(root, d.Name())
+		meta, err := readCacheMeta(dir)
+		if err != nil {
+			continue
+		}
+		meta.Key = d.Name()
+		meta.Size = dirSize(dir)
+		entries = append(entries, *meta)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].UsedAt.After(entries[j].UsedAt)
+	})
+	return entries, nil
+}
+
+// CleanCache はビルドキャッシュをすべて削除し、削除したサイズを返す
+func CleanCache(projectDir string) (int64, error) {
+	dir := GetCacheDir(projectDir)
+	size := dirSize(dir)
+	if err := os.RemoveAll(dir); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+func readCacheMeta(dir string) (*BundleCacheEntry, error) {
+	data, err := os.ReadFile(filepath.Join(dir, cacheMetaFile))
+	if err != nil {
+		return nil, err
+	}
+	var meta BundleCacheEntry
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+func writeCacheMeta(dir string, meta *BundleCacheEntry) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, cacheMetaFile), data, 0644)
+}
+
+// copyBundleFiles はバンドルの js / css / ソースマップを srcDir から dstDir にコピーする
+func copyBundleFiles(srcDir, dstDir, bundle string) error {
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		return err
+	}
+	for _, ext := range bundleFileExts {
+		src := filepath.Join(srcDir, bundle+ext)
+		if _, err := os.Stat(src); err != nil {
+			continue
+		}
+		if err := copyFile(src, filepath.Join(dstDir, bundle+ext)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dirSize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,165 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// cybozuCDN は cybozu CDN のベースURL
+const cybozuCDN = "https://js.cybozu.com"
+
+// cdnLibrary は cybozu CDN で UMD 版が配布されているライブラリ
+type cdnLibrary struct {
+	name     string
+	path     string            // CDN 上のパス（{version} をバージョンに置き換える）
+	globals  map[string]string // import するモジュール → UMD のグローバル変数
+	requires []string          // 先に読み込む必要があるライブラリ
+	maxMajor int               // UMD 版が提供されている最大のメジャーバージョン（0 は制限なし）
+}
+
+// cdnLibraries は外部化できるライブラリ（manifest.json にはこの順に並べる）
+var cdnLibraries = []cdnLibrary{
+	{
+		name:     "react",
+		path:     "/react/v{version}/react.production.min.js",
+		globals:  map[string]string{"react": "React"},
+		maxMajor: 18,
+	},
+	{
+		name:     "react-dom",
+		path:     "/react/v{version}/react-dom.production.min.js",
+		globals:  map[string]string{"react-dom": "ReactDOM", "react-dom/client": "ReactDOM"},
+		requires: []string{"react"},
+		maxMajor: 18,
+	},
+	{
+		name:    "vue",
+		path:    "/vue/v{version}/vue.global.prod.js",
+		globals: map[string]string{"vue": "Vue"},
+	},
+	{
+		name:    "jquery",
+		path:    "/jquery/{version}/jquery.min.js",
+		globals: map[string]string{"jquery": "jQuery"},
+	},
+	{
+		name:    "dayjs",
+		path:    "/dayjs/v{version}/dayjs.min.js",
+		globals: map[string]string{"dayjs": "dayjs"},
+	},
+	{
+		name:    "lodash",
+		path:    "/lodash/{version}/lodash.min.js",
+		globals: map[string]string{"lodash": "_"},
+	},
+}
+
+// ExternalLibrary はバンドルせずに CDN から読み込むライブラリ
+type ExternalLibrary struct {
+	Name    string
+	Version string
+	URL     string
+	Globals map[string]string
+}
+
+// ExternalGlobals はライブラリのモジュール → グローバル変数をまとめて返す
+func ExternalGlobals(libs []ExternalLibrary) map[string]string {
+	globals := map[string]string{}
+	for _, lib := range libs {
+		for module, global := range lib.Globals {
+			globals[module] = global
+		}
+	}
+	return globals
+}
+
+// ExternalLibraryNames は外部化できるパッケージ名を返す
+func ExternalLibraryNames() []string {
+	names := make([]string, len(cdnLibraries))
+	for i, lib := range cdnLibraries {
+		names[i] = lib.name
+	}
+	return names
+}
+
+var semverPattern = regexp.MustCompile(`\d+\.\d+\.\d+`)
+
+// ResolveExternals は外部化するパッケージを、インストール済みのバージョンの CDN URL に解決する
+// バージョンは node_modules の package.json、なければ package.json の dependencies から読み取る
+func ResolveExternals(projectDir string, names []string) ([]ExternalLibrary, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	var deps map[string]string
+	if data, err := os.ReadFile(filepath.Join(projectDir, "package.json")); err == nil {
+		var pkg struct {
+			Dependencies map[string]string `json:"dependencies"`
+		}
+		if err := json.Unmarshal(data, &pkg); err != nil {
+			return nil, fmt.Errorf("package.json の解析エラー: %w", err)
+		}
+		deps = pkg.Dependencies
+	}
+
+	for _, name := range names {
+		if !slices.ContainsFunc(cdnLibraries, func(lib cdnLibrary) bool { return lib.name == name }) {
+			return nil, fmt.Errorf("%s は外部化できません（対応しているパッケージ: %s）", name, strings.Join(ExternalLibraryNames(), ", "))
+		}
+	}
+
+	var libs []ExternalLibrary
+	for _, lib := range cdnLibraries {
+		if !slices.Contains(names, lib.name) {
+			continue
+		}
+		for _, req := range lib.requires {
+			if !slices.Contains(names, req) {
+				return nil, fmt.Errorf("%s を外部化するには %s も指定してください", lib.name, req)
+			}
+		}
+
+		version := installedVersion(projectDir, lib.name)
+		if version == "" {
+			version = semverPattern.FindString(deps[lib.name])
+		}
+		if version == "" {
+			return nil, fmt.Errorf("%s のバージョンがわかりません。npm install を実行してください", lib.name)
+		}
+		if lib.maxMajor > 0 {
+			major, _ := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+			if major > lib.maxMajor {
+				return nil, fmt.Errorf("%s %s は UMD 版が提供されていないため外部化できません（%d.x 以下のみ対応）", lib.name, version, lib.maxMajor)
+			}
+		}
+
+		libs = append(libs, ExternalLibrary{
+			Name:    lib.name,
+			Version: version,
+			URL:     cybozuCDN + strings.ReplaceAll(lib.path, "{version}", version),
+			Globals: lib.globals,
+		})
+	}
+	return libs, nil
+}
+
+// installedVersion は node_modules にインストールされているパッケージのバージョンを返す
+func installedVersion(projectDir, name string) string {
+	data, err := os.ReadFile(filepath.Join(projectDir, "node_modules", name, "package.json"))
+	if err != nil {
+		return ""
+	}
+	var pkg struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return ""
+	}
+	return pkg.Version
+}
@@ -7,16 +7,21 @@ import (
 	"crypto/rsa"
 	"crypto/sha1"
 	"crypto/x509"
+	"encoding/json"
 	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/kintone/kpdev/internal/config"
 	"github.com/kintone/kpdev/internal/generator"
 )
 
 // PackageDevPlugin は開発用プラグインZIPを作成する
-func PackageDevPlugin(projectDir string) (string, error) {
+// owner はローダーが Dev server に接続できないときのバナーに表示される管理者名
+func PackageDevPlugin(projectDir, owner string) (string, error) {
 	managedDir := filepath.Join(config.GetConfigDir(projectDir), "managed")
 	devPluginDir := filepath.Join(managedDir, "dev-plugin")
 	zipPath := filepath.Join(managedDir, "dev-plugin.zip")
@@ -28,8 +33,26 @@ func PackageDevPlugin(projectDir string) (string, error) {
 		return "", err
 	}
 
+	// manifest.json に追加したリソース（CDN・ローカルファイル）を開発用プラグインにも反映
+	resources, err := generator.SyncLoaderResources(projectDir)
+	if err != nil {
+		return "", err
+	}
+	// ローカルファイルは managed/ にコピーせず、プロジェクトから直接 ZIP に格納する
+	extra := map[string]string{}
+	for _, res := range resources {
+		extra[res] = filepath.Join(projectDir, filepath.FromSlash(res))
+	}
+
+	// ローダーJSの管理者名を埋め込む
+	ownerJSON, err := json.Marshal(owner)
+	if err != nil {
+		return "", err
+	}
+	replacer := strings.NewReplacer(generator.LoaderOwnerPlaceholder, string(ownerJSON))
+
 	// プラグインZIPを作成
-	if err := createPluginZip(devPluginDir, zipPath, privateKey); err != nil {
+	if err := createPluginZip(devPluginDir, zipPath, privateKey, replacer, extra); err != nil {
 		return "", err
 	}
 
@@ -49,17 +72,20 @@ func PackageProdPlugin(projectDir, distDir string) (string, error) {
 	// TODO: バージョン取得
 
 	zipPath := filepath.Join(projectDir, "dist", "plugin.zip")
-	if err := createPluginZip(distDir, zipPath, privateKey); err != nil {
+	if err := createPluginZip(distDir, zipPath, privateKey, nil, nil); err != nil {
 		return "", err
 	}
 
 	return zipPath, nil
 }
 
-func createPluginZip(srcDir, dstPath string, privateKey *rsa.PrivateKey) error {
+// createPluginZip は署名付きプラグインZIPを作成する
+// replacer を指定した場合は .js ファイルの内容を置換してから格納する
+// extra は srcDir 以外から追加で格納するファイル（ZIP 内のパス → ファイルのパス）
+func createPluginZip(srcDir, dstPath string, privateKey *rsa.PrivateKey, replacer *strings.Replacer, extra map[string]string) error {
 	// 1. contents.zip を作成
 	contentsZipPath := dstPath + ".contents"
-	if err := createContentsZip(srcDir, contentsZipPath); err != nil {
+	if err := createContentsZip(srcDir, contentsZipPath, replacer, extra); err != nil {
 		return err
 	}
 	defer os.Remove(contentsZipPath)
@@ -123,7 +149,7 @@ func createPluginZip(srcDir, dstPath string, privateKey *rsa.PrivateKey) error {
 	return nil
 }
 
-func createContentsZip(srcDir, dstPath string) error {
+func createContentsZip(srcDir, dstPath string, replacer *strings.Replacer, extra map[string]string) error {
 	zipFile, err := os.Create(dstPath)
 	if err != nil {
 		return err
@@ -133,7 +159,7 @@ func createContentsZip(srcDir, dstPath string) error {
 	zipWriter := zip.NewWriter(zipFile)
 	defer zipWriter.Close()
 
-	return filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+	err = filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -147,20 +173,44 @@ func createContentsZip(srcDir, dstPath string) error {
 			return err
 		}
 
-		// Zipエントリを作成
-		writer, err := zipWriter.Create(relPath)
-		if err != nil {
+		return addZipFile(zipWriter, relPath, path, replacer)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(extra)) {
+		if err := addZipFile(zipWriter, name, extra[name], nil); err != nil {
 			return err
 		}
+	}
+	return nil
+}
+
+// addZipFile はファイルを name で ZIP に格納する
+func addZipFile(zipWriter *zip.Writer, name, path string, replacer *strings.Replacer) error {
+	// Zipエントリを作成
+	writer, err := zipWriter.Create(name)
+	if err != nil {
+		return err
+	}
 
-		// ファイルを読み込んで書き込み
-		file, err := os.Open(path)
+	if replacer != nil && strings.HasSuffix(name, ".js") {
+		data, err := os.ReadFile(path)
 		if err != nil {
 			return err
 		}
-		defer file.Close()
+		_, err = io.WriteString(writer, replacer.Replace(string(data)))
+		return err
+	}
 
-		_, err = io.Copy(writer, file)
+	// ファイルを読み込んで書き込み
+	file, err := os.Open(path)
+	if err != nil {
 		return err
-	})
+	}
+	defer file.Close()
+
+	_, err = io.Copy(writer, file)
+	return err
 }
@@ -0,0 +1,120 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kintone/kpdev/internal/config"
+)
+
+// MaxPluginFileSize は kintone に読み込めるプラグインファイル（ZIP）のサイズの上限
+const MaxPluginFileSize = 20 << 20
+
+// OutputSize はビルドした出力のサイズ
+type OutputSize struct {
+	// Name は desktop.js などの出力名（ZIP 全体は zip）
+	Name string
+	Size int64
+	// Previous は前回のビルドのサイズ（HasPrevious が false なら前回の記録なし）
+	Previous    int64
+	HasPrevious bool
+	// Limits は build.budgets の上限
+	Limits config.SizeLimits
+}
+
+// Delta は前回のビルドからの増減を返す
+func (s OutputSize) Delta() int64 {
+	return s.Size - s.Previous
+}
+
+// OverWarn は警告の上限を超えているかどうかを返す
+func (s OutputSize) OverWarn() bool {
+	return s.Limits.Warn > 0 && s.Size > s.Limits.Warn
+}
+
+// OverError はエラーの上限を超えているかどうかを返す
+func (s OutputSize) OverError() bool {
+	return s.Limits.Error > 0 && s.Size > s.Limits.Error
+}
+
+// CheckUploadLimits はプラグインファイルが kintone の上限に収まるかを確認する（アップロード前に呼ぶ）
+func CheckUploadLimits(zipPath string) error {
+	info, err := os.Stat(zipPath)
+	if err != nil {
+		return err
+	}
+	if info.Size() > MaxPluginFileSize {
+		return fmt.Errorf("プラグインファイルが kintone の上限 %s を %s 超えています: %s",
+			config.FormatSize(MaxPluginFileSize), config.FormatSize(info.Size()-MaxPluginFileSize), filepath.Base(zipPath))
+	}
+	return nil
+}
+
+// measureOutputs は build.budgets の対象の出力のサイズを測る（ビルドしなかった出力は含めない）
+func measureOutputs(pluginDir, zipPath string, budgets map[string]config.SizeLimits) ([]OutputSize, error) {
+	var sizes []OutputSize
+	for _, name := range config.BudgetTargets {
+		path := zipPath
+		if name != "zip" {
+			path = filepath.Join(pluginDir, strings.TrimPrefix(filepath.Ext(name), "."), name)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		sizes = append(sizes, OutputSize{Name: name, Size: info.Size(), Limits: budgets[name]})
+	}
+	return sizes, nil
+}
+
+// recordOutputSizes は前回のビルドのサイズを sizes に設定し、今回のサイズを .kpdev/build-sizes.json に記録する
+func recordOutputSizes(projectDir, key, version string, sizes []OutputSize) (*config.BuildSizeRecord, error) {
+	history, err := config.LoadBuildSizes(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := history.Builds[key]
+	if previous != nil {
+		for i := range sizes {
+			if size, ok := previous.Sizes[sizes[i].Name]; ok {
+				sizes[i].Previous = size
+				sizes[i].HasPrevious = true
+			}
+		}
+	}
+
+	record := &config.BuildSizeRecord{
+		Version: version,
+		BuiltAt: time.Now().Format(time.RFC3339),
+		Sizes:   map[string]int64{},
+	}
+	for _, s := range sizes {
+		record.Sizes[s.Name] = s.Size
+	}
+	history.Builds[key] = record
+	if err := history.Save(projectDir); err != nil {
+		return nil, err
+	}
+	return previous, nil
+}
+
+// budgetError は上限（error）を超えた出力をまとめたエラーを返す
+func budgetError(sizes []OutputSize) error {
+	var over []string
+	for _, s := range sizes {
+		if s.OverError() {
+			over = append(over, fmt.Sprintf("%s %s（上限 %s）", s.Name, config.FormatSize(s.Size), config.FormatSize(s.Limits.Error)))
+		}
+	}
+	if len(over) == 0 {
+		return nil
+	}
+	return fmt.Errorf("build.budgets の上限を超えています: %s", strings.Join(over, ", "))
+}
@@ -0,0 +1,188 @@
+package plugin
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kintone/kpdev/internal/generator"
+)
+
+// Source は kpdev adopt で取り込む既存プラグイン（ソースディレクトリまたはプラグインZIP）
+// パスはすべて manifest.json のあるディレクトリからの相対パス（/ 区切り）
+type Source struct {
+	// Manifest は既存プラグインの manifest.json
+	Manifest map[string]interface{}
+	// PluginID は ZIP の PUBKEY から求めたプラグインID（ソースディレクトリの場合は空）
+	PluginID string
+	// IsZip はプラグインZIPから読み込んだ場合 true
+	IsZip bool
+	// Dir はソースディレクトリ（manifest.json のあるディレクトリ）。ZIP の場合は空
+	Dir string
+
+	files map[string][]byte // ZIP の内容
+}
+
+// OpenSource は既存プラグインのソースディレクトリまたはプラグインZIPを開く
+// ディレクトリの場合は manifest.json を直下・src/・plugin/ の順に探す
+func OpenSource(p string) (*Source, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var src *Source
+	if info.IsDir() {
+		src, err = openSourceDir(p)
+	} else {
+		src, err = openSourceZip(p)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := src.Read("manifest.json")
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &src.Manifest); err != nil {
+		return nil, fmt.Errorf("manifest.json の解析エラー: %w", err)
+	}
+	return src, nil
+}
+
+func openSourceDir(dir string) (*Source, error) {
+	for _, candidate := range []string{".", "src", "plugin"} {
+		base := filepath.Join(dir, candidate)
+		if _, err := os.Stat(filepath.Join(base, "manifest.json")); err == nil {
+			abs, err := filepath.Abs(base)
+			if err != nil {
+				return nil, err
+			}
+			return &Source{Dir: abs}, nil
+		}
+	}
+	return nil, fmt.Errorf("%s に manifest.json が見つかりません（直下・src/・plugin/ を探しました）", dir)
+}
+
+func openSourceZip(zipPath string) (*Source, error) {
+	data, err := os.ReadFile(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	files, err := readZipFiles(data)
+	if err != nil {
+		return nil, fmt.Errorf("ZIP の読み込みエラー: %w", err)
+	}
+
+	src := &Source{IsZip: true}
+
+	// 署名付きのプラグインZIP（contents.zip + PUBKEY + SIGNATURE）なら中身を展開する
+	if contents, ok := files["contents.zip"]; ok {
+		if pubkey, ok := files["PUBKEY"]; ok {
+			src.PluginID = generator.PluginIDFromPublicKeyDER(pubkey)
+		}
+		files, err = readZipFiles(contents)
+		if err != nil {
+			return nil, fmt.Errorf("contents.zip の読み込みエラー: %w", err)
+		}
+	}
+
+	if _, ok := files["manifest.json"]; !ok {
+		return nil, fmt.Errorf("%s に manifest.json が含まれていません", filepath.Base(zipPath))
+	}
+	src.files = files
+	return src, nil
+}
+
+func readZipFiles(data []byte) (map[string][]byte, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{}
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		files[path.Clean(strings.TrimPrefix(f.Name, "/"))] = content
+	}
+	return files, nil
+}
+
+// Read は manifest.json のあるディレクトリからの相対パスでファイルを読み込む
+func (s *Source) Read(rel string) ([]byte, error) {
+	rel = path.Clean(rel)
+	if strings.HasPrefix(rel, "../") || rel == ".." {
+		if s.IsZip {
+			return nil, fmt.Errorf("%s は ZIP の外を参照しています", rel)
+		}
+		// ソースディレクトリでは src/ の外の共通モジュールなども参照できる
+		return os.ReadFile(filepath.Join(s.Dir, filepath.FromSlash(rel)))
+	}
+
+	if s.IsZip {
+		data, ok := s.files[rel]
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", rel, fs.ErrNotExist)
+		}
+		return data, nil
+	}
+	return os.ReadFile(filepath.Join(s.Dir, filepath.FromSlash(rel)))
+}
+
+// Exists はファイルが存在するかを返す
+func (s *Source) Exists(rel string) bool {
+	if s.IsZip {
+		_, ok := s.files[path.Clean(rel)]
+		return ok
+	}
+	info, err := os.Stat(filepath.Join(s.Dir, filepath.FromSlash(path.Clean(rel))))
+	return err == nil && !info.IsDir()
+}
+
+// Files は含まれるファイルの一覧を返す
+// ソースディレクトリの場合は node_modules とドットで始まるディレクトリを除く
+func (s *Source) Files() []string {
+	var files []string
+	if s.IsZip {
+		for name := range s.files {
+			files = append(files, name)
+		}
+	} else {
+		filepath.WalkDir(s.Dir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() {
+				if p != s.Dir && (d.Name() == "node_modules" || strings.HasPrefix(d.Name(), ".")) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			rel, _ := filepath.Rel(s.Dir, p)
+			files = append(files, filepath.ToSlash(rel))
+			return nil
+		})
+	}
+	sort.Strings(files)
+	return files
+}
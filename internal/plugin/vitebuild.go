@@ -0,0 +1,194 @@
+package plugin
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kintone/kpdev/internal/config"
+	"github.com/kintone/kpdev/internal/generator"
+)
+
+// bundleOrder はバンドルをビルドする順序
+var bundleOrder = []string{"main", "desktop", "mobile", "config"}
+
+// viteOutDir は Vite がバンドルごとに出力する一時ディレクトリ（dist/ からの相対）
+const viteOutDir = ".vite"
+
+// bundleFileExts は Vite がバンドルごとに出力するファイルの拡張子
+var bundleFileExts = []string{".js", ".css", ".js.map"}
+
+// bundleOutDir はバンドルの出力先を返す
+func bundleOutDir(distDir, bundle string) string {
+	return filepath.Join(distDir, viteOutDir, bundle)
+}
+
+// runViteBuild はバンドルごとに vite build を実行し、バンドルごとの所要時間を返す
+// バンドルは別々のディレクトリに出力するため並列に実行する
+// 古い vite.config.ts は出力先を変えられないため、dist/ に順番に出力してから移動する
+// cacheKeys にキーがあるバンドルはキャッシュがあれば再利用し、なければビルド後に保存する
+func runViteBuild(projectDir string, cfg *config.Config, opts *BuildOptions, externals []ExternalLibrary, defines map[string]string, parallel bool, cacheKeys map[string]string) ([]BuildStep, error) {
+	viteConfigPath := filepath.Join(config.GetConfigDir(projectDir), "vite.config.ts")
+	env := []string{generator.ViteEntriesEnv(cfg), generator.ViteExternalsEnv(ExternalGlobals(externals))}
+	env = append(env, generator.ViteProfileEnv(opts.Profile.RemoveConsole, opts.Profile.Sourcemap, defines)...)
+	env = append(env, generator.ViteAssetsEnv(cfg.AssetsBaseURL()))
+	distDir := filepath.Join(projectDir, "dist")
+
+	// バンドルごとにビルド（desktop / mobile は個別のエントリーがある場合のみ）
+	var targets []string
+	bundles := cfg.Bundles()
+	for _, bundle := range bundleOrder {
+		if _, ok := bundles[bundle]; ok {
+			targets = append(targets, bundle)
+		}
+	}
+
+	steps := make([]BuildStep, len(targets))
+	errs := make([]error, len(targets))
+	var logMu sync.Mutex
+	build := func(i int) {
+		bundle := targets[i]
+		outDir := bundleOutDir(distDir, bundle)
+		start := time.Now()
+		key := cacheKeys[bundle]
+		if key != "" {
+			if ok, err := restoreBundleCache(projectDir, key, bundle, outDir); ok || err != nil {
+				errs[i] = err
+				steps[i] = BuildStep{Name: bundle, Duration: time.Since(start), Cached: true}
+				return
+			}
+		}
+		if parallel {
+			// 並列に実行するため env の配列を共有しないよう、容量を切り詰めてから追加する
+			errs[i] = runSingleViteBuild(projectDir, viteConfigPath, bundle, append(slices.Clip(env), "KPDEV_OUT_DIR="+outDir), opts, &logMu)
+		} else {
+			errs[i] = runSingleViteBuild(projectDir, viteConfigPath, bundle, env, opts, &logMu)
+			if errs[i] == nil {
+				errs[i] = moveBundleFiles(distDir, outDir, bundle)
+			}
+		}
+		if errs[i] == nil && key != "" {
+			// キャッシュに保存できなくてもビルドは成功させる（次回ビルドし直すだけ）
+			storeBundleCache(projectDir, key, bundle, outDir)
+		}
+		steps[i] = BuildStep{Name: bundle, Duration: time.Since(start)}
+	}
+
+	if parallel {
+		var wg sync.WaitGroup
+		for i := range targets {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				build(i)
+			}()
+		}
+		wg.Wait()
+	} else {
+		for i := range targets {
+			if build(i); errs[i] != nil {
+				break
+			}
+		}
+	}
+
+	var failed []string
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("[%s] %v", targets[i], err))
+		}
+	}
+	if len(failed) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(failed, "\n"))
+	}
+	return steps, nil
+}
+
+func runSingleViteBuild(projectDir, viteConfigPath, entry string, env []string, opts *BuildOptions, logMu *sync.Mutex) error {
+	args := []string{"vite", "build", "--config", viteConfigPath}
+
+	if !opts.Profile.Minify {
+		args = append(args, "--minify", "false")
+	}
+
+	cmd := exec.Command("npx", args...)
+	cmd.Dir = projectDir
+	cmd.Env = append(append(os.Environ(), "VITE_BUILD_ENTRY="+entry), env...)
+
+	// Log を指定した場合はバンドル名を付けて逐次表示し、それ以外は失敗時のみ表示する
+	if opts.Log != nil {
+		w := &prefixWriter{w: opts.Log, mu: logMu, prefix: "[" + entry + "] "}
+		cmd.Stdout = w
+		cmd.Stderr = w
+		err := cmd.Run()
+		w.Flush()
+		return err
+	}
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w\n%s", err, string(output))
+	}
+
+	return nil
+}
+
+// moveBundleFiles は dist/ に出力されたバンドルをバンドルごとの出力先に移動する
+func moveBundleFiles(distDir, outDir, bundle string) error {
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return err
+	}
+	for _, ext := range bundleFileExts {
+		src := filepath.Join(distDir, bundle+ext)
+		if _, err := os.Stat(src); err != nil {
+			continue
+		}
+		if err := os.Rename(src, filepath.Join(outDir, bundle+ext)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prefixWriter は行ごとに接頭辞を付けて書き込む
+// 並列に実行する Vite の出力が行の途中で混ざらないよう、mu で排他する
+type prefixWriter struct {
+	w      io.Writer
+	mu     *sync.Mutex
+	prefix string
+	buf    []byte
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.buf = append(p.buf, b...)
+	for {
+		i := bytes.IndexByte(p.buf, '\n')
+		if i < 0 {
+			break
+		}
+		p.writeLine(p.buf[:i])
+		p.buf = p.buf[i+1:]
+	}
+	return len(b), nil
+}
+
+// Flush は改行で終わっていない残りの出力を書き込む
+func (p *prefixWriter) Flush() {
+	if len(p.buf) > 0 {
+		p.writeLine(p.buf)
+		p.buf = nil
+	}
+}
+
+func (p *prefixWriter) writeLine(line []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintf(p.w, "%s%s\n", p.prefix, bytes.TrimRight(line, "\r"))
+}
@@ -0,0 +1,58 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/kintone/kpdev/internal/config"
+)
+
+// 並列ビルドでバンドルごとに自分の KPDEV_OUT_DIR へ出力されることを確認する
+func TestRunViteBuildParallelOutDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("npx の代わりにシェルスクリプトを使うため")
+	}
+
+	// vite build の代わりに、VITE_BUILD_ENTRY を KPDEV_OUT_DIR に書き出す npx
+	binDir := t.TempDir()
+	script := "#!/bin/sh\nsleep 0.2\nmkdir -p \"$KPDEV_OUT_DIR\"\nprintf '%s' \"$VITE_BUILD_ENTRY\" > \"$KPDEV_OUT_DIR/entry.txt\"\n"
+	if err := os.WriteFile(filepath.Join(binDir, "npx"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	projectDir := t.TempDir()
+	cfg := &config.Config{
+		Dev: config.DevConfig{Entry: config.EntryConfig{
+			Main:    "/src/main/main.ts",
+			Desktop: "/src/desktop/main.ts",
+			Mobile:  "/src/mobile/main.ts",
+			Config:  "/src/config/main.ts",
+		}},
+		Targets: config.TargetsConfig{Desktop: true, Mobile: true},
+	}
+	opts := &BuildOptions{Profile: &config.ResolvedProfile{Name: "prod", Minify: true, RemoveConsole: true}}
+
+	// 定数を増やして env の配列に余分な容量がある状態にする
+	defines := map[string]string{"__A__": "1", "__B__": "2", "__C__": "3"}
+	steps, err := runViteBuild(projectDir, cfg, opts, nil, defines, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != len(cfg.Bundles()) {
+		t.Fatalf("steps = %d, want %d", len(steps), len(cfg.Bundles()))
+	}
+
+	for bundle := range cfg.Bundles() {
+		data, err := os.ReadFile(filepath.Join(bundleOutDir(filepath.Join(projectDir, "dist"), bundle), "entry.txt"))
+		if err != nil {
+			t.Fatalf("%s: %v", bundle, err)
+		}
+		if got := strings.TrimSpace(string(data)); got != bundle {
+			t.Errorf("%s の出力先に %s がビルドされました", bundle, got)
+		}
+	}
+}
@@ -0,0 +1,497 @@
+package ui
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mattn/go-isatty"
+)
+
+// DevState はダッシュボードに表示する状態
+type DevState int
+
+const (
+	DevStatePending DevState = iota
+	DevStateBusy
+	DevStateOK
+	DevStateSkipped
+	DevStateError
+)
+
+// 各ペインに保持する最大件数
+const (
+	dashboardMaxRebuilds = 5
+	dashboardMaxErrors   = 5
+	dashboardMaxLogs     = 8
+)
+
+// DevDashboardOptions はダッシュボードの表示内容とキー操作を定義する
+type DevDashboardOptions struct {
+	PluginID string
+	Origin   string
+	// OnRedeploy は r キーで呼ばれる（別 goroutine で実行される）
+	OnRedeploy func()
+	// OnOpen は o キーで呼ばれる
+	OnOpen func()
+}
+
+// DevDashboard は kpdev dev の状態を表示する
+// stdout が TTY の場合は bubbletea のダッシュボード、それ以外は行単位のプレーン出力になる
+type DevDashboard struct {
+	opts     DevDashboardOptions
+	program  *tea.Program
+	queue    chan tea.Msg
+	stop     chan struct{}
+	stopOnce sync.Once
+	mu       sync.Mutex
+}
+
+// NewDevDashboard はダッシュボードを作成する
+func NewDevDashboard(opts DevDashboardOptions) *DevDashboard {
+	d := &DevDashboard{
+		opts: opts,
+		stop: make(chan struct{}),
+	}
+	if !Quiet && isatty.IsTerminal(os.Stdout.Fd()) && isatty.IsTerminal(os.Stdin.Fd()) {
+		d.program = tea.NewProgram(newDashboardModel(opts), tea.WithAltScreen())
+		// Program.Send は Run 開始までブロックするため、キュー経由で順番に送る
+		d.queue = make(chan tea.Msg, 256)
+		go func() {
+			for msg := range d.queue {
+				d.program.Send(msg)
+			}
+		}()
+	}
+	return d
+}
+
+func (d *DevDashboard) send(msg tea.Msg) {
+	d.queue <- msg
+}
+
+// Interactive はTUIダッシュボードで表示しているかを返す
+func (d *DevDashboard) Interactive() bool {
+	return d.program != nil
+}
+
+// Run はユーザーが終了するか Stop が呼ばれるまでブロックする
+// ユーザー操作（q / Ctrl+C / シグナル）で終了した場合は true を返す
+func (d *DevDashboard) Run() (bool, error) {
+	if d.program == nil {
+		sigChan := SetupSignalHandler()
+		defer signal.Stop(sigChan)
+		select {
+		case <-sigChan:
+			return true, nil
+		case <-d.stop:
+			return false, nil
+		}
+	}
+
+	model, err := d.program.Run()
+	if errors.Is(err, tea.ErrInterrupted) || errors.Is(err, tea.ErrProgramKilled) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	m, _ := model.(dashboardModel)
+	return m.userQuit, nil
+}
+
+// Stop はダッシュボードを終了する（Dev server が終了した場合など）
+func (d *DevDashboard) Stop() {
+	d.stopOnce.Do(func() {
+		close(d.stop)
+		if d.program != nil {
+			d.program.Quit()
+		}
+	})
+}
+
+// ServerStatus は Dev server の状態を更新する
+func (d *DevDashboard) ServerStatus(state DevState, text string) {
+	if d.program != nil {
+		d.send(dashServerMsg{state: state, text: text})
+		return
+	}
+	d.println(stateIcon(state) + " Dev server: " + text)
+}
+
+// LoaderStatus はローダーのデプロイ状態を更新する
+func (d *DevDashboard) LoaderStatus(state DevState, text string) {
+	if d.program != nil {
+		d.send(dashLoaderMsg{state: state, text: text})
+		return
+	}
+	d.println(stateIcon(state) + " ローダー: " + text)
+}
+
+// Rebuild はエントリの再ビルド結果を追加する
+func (d *DevDashboard) Rebuild(entry string, duration time.Duration) {
+	if d.program != nil {
+		d.send(dashRebuildMsg{at: time.Now(), entry: entry, duration: duration})
+		return
+	}
+	d.println(fmt.Sprintf("%s %s をビルド (%s)", SuccessStyle.Render(IconSuccess), entry, FormatDuration(duration)))
+}
+
+// Error はエラーを追加する
+func (d *DevDashboard) Error(text string) {
+	if d.program != nil {
+		d.send(dashErrorMsg{at: time.Now(), text: text})
+		return
+	}
+	d.println(ErrorStyle.Render(IconError) + " " + text)
+}
+
+// BrowserReport はブラウザから転送されたエラー・console 出力を追加する
+func (d *DevDashboard) BrowserReport(level, location, message string) {
+	text := "[browser] " + location + " " + message
+	isError := level == "uncaught" || level == "unhandledrejection" || level == "console.error"
+	if d.program != nil {
+		if isError {
+			d.send(dashErrorMsg{at: time.Now(), text: text})
+		} else {
+			d.send(dashLogMsg{at: time.Now(), text: level + " " + text})
+		}
+		return
+	}
+	prefix := MutedStyle.Render("[browser] " + location)
+	switch {
+	case isError:
+		d.println(ErrorStyle.Render(IconError) + " " + prefix + " " + message)
+	case level == "console.warn":
+		d.println(WarnStyle.Render(IconWarn) + " " + prefix + " " + message)
+	default:
+		d.println(InfoStyle.Render(IconInfo) + " " + prefix + " " + message)
+	}
+}
+
+// Log はログを追加する
+func (d *DevDashboard) Log(text string) {
+	if d.program != nil {
+		d.send(dashLogMsg{at: time.Now(), text: text})
+		return
+	}
+	d.println(text)
+}
+
+func (d *DevDashboard) println(line string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	fmt.Println(line)
+}
+
+// ViteWriter は Vite の出力を受け取り、行ごとに解析してダッシュボードに反映する Writer を返す
+func (d *DevDashboard) ViteWriter(stderr bool) io.Writer {
+	return &dashboardLineWriter{dashboard: d, stderr: stderr}
+}
+
+var (
+	ansiEscapeRe   = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+	kpdevBuildRe   = regexp.MustCompile(`\[kpdev\] build (\S+) (\d+)ms`)
+	kpdevBrowserRe = regexp.MustCompile(`\[kpdev:browser\] (\S+) (\S+) (.*)$`)
+	viteReadyRe    = regexp.MustCompile(`ready in`)
+	viteErrorLevel = regexp.MustCompile(`(?i)\berror\b`)
+)
+
+// handleViteLine は Vite の出力1行を分類する
+func (d *DevDashboard) handleViteLine(line string, stderr bool) {
+	plain := strings.TrimRight(ansiEscapeRe.ReplaceAllString(line, ""), " \r")
+	if strings.TrimSpace(plain) == "" {
+		return
+	}
+
+	if m := kpdevBuildRe.FindStringSubmatch(plain); m != nil {
+		ms, _ := strconv.Atoi(m[2])
+		d.Rebuild(m[1], time.Duration(ms)*time.Millisecond)
+		return
+	}
+
+	if m := kpdevBrowserRe.FindStringSubmatch(plain); m != nil {
+		d.BrowserReport(m[1], m[2], m[3])
+		return
+	}
+
+	if viteReadyRe.MatchString(plain) {
+		d.ServerStatus(DevStateOK, "稼働中 "+d.opts.Origin)
+	}
+
+	if stderr && viteErrorLevel.MatchString(plain) {
+		d.Error(strings.TrimSpace(plain))
+		return
+	}
+
+	if d.program != nil {
+		d.Log(strings.TrimSpace(plain))
+		return
+	}
+	// プレーン出力では Vite の色付き出力をそのまま表示
+	d.println(line)
+}
+
+// dashboardLineWriter は書き込まれたバイト列を行単位に分割する
+type dashboardLineWriter struct {
+	dashboard *DevDashboard
+	stderr    bool
+	buf       bytes.Buffer
+	mu        sync.Mutex
+}
+
+func (w *dashboardLineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf.Write(p)
+	for {
+		line, err := w.buf.ReadString('\n')
+		if err != nil {
+			// 改行が来るまで残りを保持
+			w.buf.Reset()
+			w.buf.WriteString(line)
+			break
+		}
+		w.dashboard.handleViteLine(strings.TrimSuffix(line, "\n"), w.stderr)
+	}
+	return len(p), nil
+}
+
+func stateIcon(state DevState) string {
+	switch state {
+	case DevStateOK:
+		return SuccessStyle.Render(IconSuccess)
+	case DevStateError:
+		return ErrorStyle.Render(IconError)
+	case DevStateSkipped:
+		return WarnStyle.Render(IconWarn)
+	case DevStateBusy:
+		return InfoStyle.Render("○")
+	default:
+		return MutedStyle.Render("○")
+	}
+}
+
+// FormatDuration はビルド時間などを 850ms / 1.23s の形式にする
+func FormatDuration(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return fmt.Sprintf("%.2fs", d.Seconds())
+}
+
+// ---- bubbletea model ----
+
+type dashServerMsg struct {
+	state DevState
+	text  string
+}
+
+type dashLoaderMsg struct {
+	state DevState
+	text  string
+}
+
+type dashRebuildMsg struct {
+	at       time.Time
+	entry    string
+	duration time.Duration
+}
+
+type dashErrorMsg struct {
+	at   time.Time
+	text string
+}
+
+type dashLogMsg struct {
+	at   time.Time
+	text string
+}
+
+type dashboardLine struct {
+	at   time.Time
+	text string
+}
+
+type dashboardModel struct {
+	opts        DevDashboardOptions
+	width       int
+	serverState DevState
+	serverText  string
+	loaderState DevState
+	loaderText  string
+	loaderAt    time.Time
+	rebuilds    []dashRebuildMsg
+	errors      []dashboardLine
+	logs        []dashboardLine
+	userQuit    bool
+}
+
+func newDashboardModel(opts DevDashboardOptions) dashboardModel {
+	return dashboardModel{
+		opts:        opts,
+		width:       80,
+		serverState: DevStateBusy,
+		serverText:  "起動中...",
+		loaderState: DevStatePending,
+		loaderText:  "未確認",
+	}
+}
+
+func (m dashboardModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c":
+			m.userQuit = true
+			return m, tea.Quit
+		case "r":
+			if m.opts.OnRedeploy != nil && m.loaderState != DevStateBusy {
+				m.loaderState = DevStateBusy
+				m.loaderText = "再デプロイ中..."
+				go m.opts.OnRedeploy()
+			}
+		case "o":
+			if m.opts.OnOpen != nil {
+				go m.opts.OnOpen()
+			}
+		case "c":
+			m.rebuilds = nil
+			m.errors = nil
+			m.logs = nil
+			return m, tea.ClearScreen
+		}
+	case dashServerMsg:
+		m.serverState = msg.state
+		m.serverText = msg.text
+	case dashLoaderMsg:
+		m.loaderState = msg.state
+		m.loaderText = msg.text
+		m.loaderAt = time.Now()
+	case dashRebuildMsg:
+		m.rebuilds = appendLimited(m.rebuilds, msg, dashboardMaxRebuilds)
+	case dashErrorMsg:
+		m.errors = appendLimited(m.errors, dashboardLine{at: msg.at, text: msg.text}, dashboardMaxErrors)
+	case dashLogMsg:
+		m.logs = appendLimited(m.logs, dashboardLine{at: msg.at, text: msg.text}, dashboardMaxLogs)
+	}
+	return m, nil
+}
+
+func appendLimited[T any](items []T, item T, max int) []T {
+	items = append(items, item)
+	if len(items) > max {
+		items = items[len(items)-max:]
+	}
+	return items
+}
+
+var (
+	dashboardPaneStyle = lipgloss.NewStyle().
+				Border(lipgloss.RoundedBorder()).
+				BorderForeground(ColorGray).
+				Padding(0, 1)
+	dashboardPaneTitle = lipgloss.NewStyle().Bold(true).Foreground(ColorCyan)
+	dashboardKeyStyle  = lipgloss.NewStyle().Bold(true).Foreground(ColorYellow)
+)
+
+func (m dashboardModel) View() string {
+	width := m.width
+	if width < 40 {
+		width = 40
+	}
+
+	header := TitleStyle.Render("kpdev dev")
+	if m.opts.PluginID != "" {
+		header += MutedStyle.Render("  Plugin ID: " + m.opts.PluginID)
+	}
+
+	halfWidth := width / 2
+	serverLines := []string{
+		stateIcon(m.serverState) + " " + m.serverText,
+		MutedStyle.Render(m.opts.Origin),
+	}
+	loaderLines := []string{stateIcon(m.loaderState) + " " + m.loaderText}
+	if !m.loaderAt.IsZero() {
+		loaderLines = append(loaderLines, MutedStyle.Render(m.loaderAt.Format("15:04:05")))
+	}
+	top := lipgloss.JoinHorizontal(lipgloss.Top,
+		renderPane("Dev server", serverLines, halfWidth),
+		renderPane("ローダー", loaderLines, width-halfWidth),
+	)
+
+	var rebuildLines []string
+	for i := len(m.rebuilds) - 1; i >= 0; i-- {
+		r := m.rebuilds[i]
+		rebuildLines = append(rebuildLines, fmt.Sprintf("%s %s %s",
+			MutedStyle.Render(r.at.Format("15:04:05")), r.entry, InfoStyle.Render(FormatDuration(r.duration))))
+	}
+	if len(rebuildLines) == 0 {
+		rebuildLines = []string{MutedStyle.Render("まだビルドされていません")}
+	}
+
+	var errorLines []string
+	for i := len(m.errors) - 1; i >= 0; i-- {
+		e := m.errors[i]
+		errorLines = append(errorLines, MutedStyle.Render(e.at.Format("15:04:05"))+" "+ErrorStyle.Render(e.text))
+	}
+	if len(errorLines) == 0 {
+		errorLines = []string{MutedStyle.Render("なし")}
+	}
+
+	var logLines []string
+	for _, l := range m.logs {
+		logLines = append(logLines, MutedStyle.Render(l.at.Format("15:04:05"))+" "+l.text)
+	}
+	if len(logLines) == 0 {
+		logLines = []string{MutedStyle.Render("-")}
+	}
+
+	keys := []string{
+		dashboardKeyStyle.Render("r") + " ローダー再デプロイ",
+		dashboardKeyStyle.Render("o") + " kintoneを開く",
+		dashboardKeyStyle.Render("c") + " クリア",
+		dashboardKeyStyle.Render("q") + " 終了",
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		top,
+		renderPane("リビルド", rebuildLines, width),
+		renderPane("エラー", errorLines, width),
+		renderPane("ログ", logLines, width),
+		" "+strings.Join(keys, MutedStyle.Render("  ·  ")),
+	)
+}
+
+// renderPane はタイトル付きの枠を描画する（行は幅に合わせて切り詰める）
+func renderPane(title string, lines []string, width int) string {
+	// 枠線(2) + パディング(2)
+	inner := width - 4
+	if inner < 10 {
+		inner = 10
+	}
+	body := []string{dashboardPaneTitle.Render(title)}
+	for _, line := range lines {
+		body = append(body, ansi.Truncate(line, inner, "…"))
+	}
+	return dashboardPaneStyle.Width(width - 2).Render(strings.Join(body, "\n"))
+}